@@ -2,8 +2,10 @@ package form3
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 // AccountsService handles communication with the Account resource methods of the Form3 API.
@@ -17,6 +19,8 @@ type AccountsService service
 func (s *AccountsService) GetAccount(ctx context.Context, id string) (*AccountResponse, *Response, error) {
 	u := fmt.Sprintf("/v1/organisation/accounts/%v", id)
 
+	ctx = withOperation(ctx, &Operation{Name: OperationGetAccount, AccountID: id})
+
 	req, err := s.client.NewRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -32,13 +36,93 @@ func (s *AccountsService) GetAccount(ctx context.Context, id string) (*AccountRe
 	return account, resp, err
 }
 
-// CreateAccount creates an account.
+// ListAccounts lists a page of accounts.
+//
+// Form3 API docs: https://www.api-docs.form3.tech/api/schemes/bacs/accounts/list-accounts
+func (s *AccountsService) ListAccounts(ctx context.Context, opts *ListAccountsOptions) (*AccountListResponse, *Response, error) {
+	u := addQuery("/v1/organisation/accounts", opts.values())
+
+	ctx = withOperation(ctx, &Operation{Name: OperationListAccounts})
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accounts := new(AccountListResponse)
+
+	resp, err := s.client.SendRequest(req, accounts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return accounts, resp, nil
+}
+
+// CreateAccount creates an account. If the client's ValidateRequests is set,
+// the account is validated first and a *ValidationError is returned without
+// calling the API.
+//
+// Every create is sent with an Idempotency-Key header, which makes it safe to
+// retry; the key can be set with WithIdempotencyKey. If the account already
+// exists, it is fetched and returned as if it was created when it matches the
+// account sent. Otherwise the conflict error is returned.
 //
 // Form3 API docs: https://www.api-docs.form3.tech/api/schemes/bacs/accounts/create-an-account
 func (s *AccountsService) CreateAccount(ctx context.Context, body *Account) (*AccountResponse, *Response, error) {
+	if s.client.ValidateRequests {
+		if err := body.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	u := "/v1/organisation/accounts"
 
-	req, err := s.client.NewRequest(ctx, http.MethodPost, u, body)
+	op := &Operation{Name: OperationCreateAccount}
+	if body != nil && body.Data != nil {
+		op.AccountID = body.Data.ID
+		op.OrganisationID = body.Data.OrganisationID
+	}
+
+	req, err := s.client.NewRequest(withOperation(ctx, op), http.MethodPost, u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set(idempotencyKeyHeader, idempotencyKeyFromContext(ctx))
+
+	account := new(AccountResponse)
+
+	resp, err := s.client.SendRequest(req, account)
+	if errors.Is(err, ErrConflict) && body != nil && body.Data != nil {
+		existing, getResp, getErr := s.GetAccount(ctx, body.Data.ID)
+		if getErr == nil && sameAccount(body, existing.Account) {
+			return existing, getResp, nil
+		}
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return account, resp, nil
+}
+
+// UpdateAccount updates the attributes of an account. Only the non-nil
+// attributes of the patch are sent. If the version in the patch is not the
+// current version of the account, a *VersionConflictError is returned and the
+// account should be fetched again before retrying.
+//
+// Form3 API docs: https://www.api-docs.form3.tech/api/schemes/bacs/accounts/patch-an-account
+func (s *AccountsService) UpdateAccount(ctx context.Context, body *AccountPatch) (*AccountResponse, *Response, error) {
+	if body == nil || body.Data == nil {
+		return nil, nil, errors.New("form3: account patch data is required")
+	}
+
+	u := fmt.Sprintf("/v1/organisation/accounts/%v", body.Data.ID)
+
+	ctx = withOperation(ctx, &Operation{Name: OperationUpdateAccount, AccountID: body.Data.ID})
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, u, body)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -46,6 +130,15 @@ func (s *AccountsService) CreateAccount(ctx context.Context, body *Account) (*Ac
 	account := new(AccountResponse)
 
 	resp, err := s.client.SendRequest(req, account)
+
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && errResp.Status == http.StatusConflict {
+		return nil, resp, &VersionConflictError{
+			AccountID: body.Data.ID,
+			Version:   body.Data.Version,
+			Err:       errResp,
+		}
+	}
 	if err != nil {
 		return nil, resp, err
 	}
@@ -59,6 +152,8 @@ func (s *AccountsService) CreateAccount(ctx context.Context, body *Account) (*Ac
 func (s *AccountsService) DeleteAccount(ctx context.Context, options *DeleteOptions) (*Response, error) {
 	u := fmt.Sprintf("/v1/organisation/accounts/%v?version=%v", options.AccountID, options.Version)
 
+	ctx = withOperation(ctx, &Operation{Name: OperationDeleteAccount, AccountID: options.AccountID})
+
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, u, nil)
 	if err != nil {
 		return nil, err
@@ -66,3 +161,29 @@ func (s *AccountsService) DeleteAccount(ctx context.Context, options *DeleteOpti
 
 	return s.client.SendRequest(req, nil)
 }
+
+// values encodes the pagination and filter options.
+func (o *ListAccountsOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	v := o.ListOptions.values()
+
+	filters := map[string]string{
+		"account_number": o.AccountNumber,
+		"bank_id":        o.BankID,
+		"bank_id_code":   string(o.BankIDCode),
+		"bic":            o.Bic,
+		"country":        o.Country,
+		"iban":           o.Iban,
+	}
+
+	for name, value := range filters {
+		if value != "" {
+			v.Set(fmt.Sprintf("filter[%v]", name), value)
+		}
+	}
+
+	return v
+}
@@ -2,12 +2,15 @@ package form3
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 
+	"github.com/froedevrolijk/form3-exercise/form3/form3test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,6 +37,20 @@ func setup() func() {
 	}
 }
 
+// setupFake points client at a new in-memory fake of the account API, for
+// tests that need the API to keep state between requests.
+func setupFake() func() {
+	fake := form3test.NewServer()
+	client = NewClient(nil)
+
+	url, _ := url.Parse(fake.URL)
+	client.BaseUrl = url
+
+	return func() {
+		fake.Close()
+	}
+}
+
 func TestGetAccount(t *testing.T) {
 	teardown := setup()
 	defer teardown()
@@ -56,6 +73,8 @@ func TestGetAccount(t *testing.T) {
 	if !cmp.Equal(want, account) {
 		t.Error(cmp.Diff(want, account))
 	}
+
+	equal(t, account.Links.Self, "/v1/organisation/accounts/"+testUUID)
 }
 
 func TestGetAccount_InvalidUUID(t *testing.T) {
@@ -76,6 +95,9 @@ func TestGetAccount_InvalidUUID(t *testing.T) {
 	want := &ErrorResponse{
 		Status:       400,
 		ErrorMessage: "id is not a valid uuid",
+		Body:         []byte(readFixture("invalid-uuid.json")),
+		Method:       http.MethodGet,
+		URL:          server.URL + u,
 	}
 
 	if !cmp.Equal(want, err) {
@@ -101,6 +123,9 @@ func TestGetAccount_NotFound(t *testing.T) {
 	want := &ErrorResponse{
 		Status:       404,
 		ErrorMessage: fmt.Sprintf("record %v does not exist", testUUID),
+		Body:         []byte(readFixture("not-found.json")),
+		Method:       http.MethodGet,
+		URL:          server.URL + u,
 	}
 
 	if !cmp.Equal(want, err) {
@@ -108,6 +133,112 @@ func TestGetAccount_NotFound(t *testing.T) {
 	}
 }
 
+func TestListAccounts(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Method, http.MethodGet)
+		equal(t, r.URL.Query().Get("page[number]"), "1")
+		equal(t, r.URL.Query().Get("page[size]"), "2")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, readFixture("account-list-response.json"))
+	})
+
+	opts := &ListAccountsOptions{
+		ListOptions: ListOptions{
+			PageNumber: 1,
+			PageSize:   2,
+		},
+	}
+
+	accounts, _, err := client.Accounts.ListAccounts(ctx, opts)
+	if err != nil {
+		t.Errorf("ListAccounts returned an error: %v", err)
+	}
+
+	want := createApiResponse[*AccountListResponse](testdataPath + "account-list-response.json")
+
+	if !cmp.Equal(want, accounts) {
+		t.Error(cmp.Diff(want, accounts))
+	}
+
+	equal(t, len(accounts.Accounts), 2)
+	equal(t, accounts.Links.Next, "/v1/organisation/accounts?page%5Bnumber%5D=2&page%5Bsize%5D=2")
+}
+
+func TestListAccounts_NoOptions(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Method, http.MethodGet)
+		equal(t, r.URL.RawQuery, "")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"data":[],"links":{"self":"/v1/organisation/accounts"}}`)
+	})
+
+	accounts, _, err := client.Accounts.ListAccounts(ctx, nil)
+	if err != nil {
+		t.Errorf("ListAccounts returned an error: %v", err)
+	}
+
+	equal(t, len(accounts.Accounts), 0)
+	equal(t, accounts.Links.Next, "")
+}
+
+func TestListAccounts_Filters(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *ListAccountsOptions
+		want string
+	}{
+		{
+			name: "bank id and account number",
+			opts: &ListAccountsOptions{BankID: "400300", AccountNumber: "41426819"},
+			want: "filter%5Baccount_number%5D=41426819&filter%5Bbank_id%5D=400300",
+		},
+		{
+			name: "country and bic",
+			opts: &ListAccountsOptions{Country: "GB", Bic: "NWBKGB22"},
+			want: "filter%5Bbic%5D=NWBKGB22&filter%5Bcountry%5D=GB",
+		},
+		{
+			name: "bank id code and iban",
+			opts: &ListAccountsOptions{BankIDCode: "GBDSC", Iban: "GB11NWBK40030041426819"},
+			want: "filter%5Bbank_id_code%5D=GBDSC&filter%5Biban%5D=GB11NWBK40030041426819",
+		},
+		{
+			name: "filters and pagination",
+			opts: &ListAccountsOptions{ListOptions: ListOptions{PageNumber: 3, PageSize: 50}, Country: "GB"},
+			want: "filter%5Bcountry%5D=GB&page%5Bnumber%5D=3&page%5Bsize%5D=50",
+		},
+		{
+			name: "empty options",
+			opts: &ListAccountsOptions{},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+				equal(t, r.Method, http.MethodGet)
+				equal(t, r.URL.RawQuery, tt.want)
+				fmt.Fprint(w, `{"data":[]}`)
+			})
+
+			_, _, err := client.Accounts.ListAccounts(ctx, tt.opts)
+			if err != nil {
+				t.Errorf("ListAccounts returned an error: %v", err)
+			}
+		})
+	}
+}
+
 func TestCreateAccount(t *testing.T) {
 	teardown := setup()
 	defer teardown()
@@ -133,6 +264,164 @@ func TestCreateAccount(t *testing.T) {
 	}
 }
 
+func TestUpdateAccount(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	body := &AccountPatch{
+		Data: &AccountPatchData{
+			ID:      testUUID,
+			Type:    "accounts",
+			Version: 0,
+			Attributes: &AccountAttributesPatch{
+				Name:                    Strings("Samantha Holder-Smith"),
+				SecondaryIdentification: String("E5F6G7H8"),
+			},
+		},
+	}
+
+	u := fmt.Sprintf("/v1/organisation/accounts/%v", testUUID)
+
+	mux.HandleFunc(u, func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Method, http.MethodPatch)
+		want := createApiResponse[*AccountPatch](testdataPath + "update-account.json")
+		equalRequestBody(t, r, want, new(AccountPatch))
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, readFixture("account-response.json"))
+	})
+
+	account, _, err := client.Accounts.UpdateAccount(ctx, body)
+	if err != nil {
+		t.Errorf("UpdateAccount returned an error: %v", err)
+	}
+
+	want := createApiResponse[*AccountResponse](testdataPath + "account-response.json")
+
+	if !cmp.Equal(want, account) {
+		t.Error(cmp.Diff(want, account))
+	}
+}
+
+func TestUpdateAccount_OnlyChangedAttributes(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	body := &AccountPatch{
+		Data: &AccountPatchData{
+			ID:      testUUID,
+			Type:    "accounts",
+			Version: 3,
+			Attributes: &AccountAttributesPatch{
+				JointAccount: Bool(false),
+			},
+		},
+	}
+
+	u := fmt.Sprintf("/v1/organisation/accounts/%v", testUUID)
+
+	mux.HandleFunc(u, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		equal(t, string(b), `{"data":{"attributes":{"joint_account":false},"id":"`+testUUID+`","type":"accounts","version":3}}`+"\n")
+		fmt.Fprint(w, readFixture("account-response.json"))
+	})
+
+	_, _, err := client.Accounts.UpdateAccount(ctx, body)
+	if err != nil {
+		t.Errorf("UpdateAccount returned an error: %v", err)
+	}
+}
+
+func TestUpdateAccount_VersionConflict(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	body := &AccountPatch{
+		Data: &AccountPatchData{
+			ID:      testUUID,
+			Type:    "accounts",
+			Version: 1,
+			Attributes: &AccountAttributesPatch{
+				Bic: String("NWBKGB42"),
+			},
+		},
+	}
+
+	u := fmt.Sprintf("/v1/organisation/accounts/%v", testUUID)
+
+	mux.HandleFunc(u, func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Method, http.MethodPatch)
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, readFixture("version-conflict.json"))
+	})
+
+	account, resp, err := client.Accounts.UpdateAccount(ctx, body)
+	assert.Nil(t, account, "expecting nil account")
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var conflictErr *VersionConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Expected a VersionConflictError, got %v", err)
+	}
+
+	want := &VersionConflictError{
+		AccountID: testUUID,
+		Version:   1,
+		Err: &ErrorResponse{
+			Status:       409,
+			ErrorMessage: "invalid version",
+			Body:         []byte(readFixture("version-conflict.json")),
+			Method:       http.MethodPatch,
+			URL:          server.URL + u,
+		},
+	}
+
+	if !cmp.Equal(want, conflictErr) {
+		t.Error(cmp.Diff(want, conflictErr))
+	}
+}
+
+func TestUpdateAccount_VersionConflictWrapped(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				err = fmt.Errorf("wrapped: %w", err)
+			}
+			return resp, err
+		}
+	})
+
+	u := fmt.Sprintf("/v1/organisation/accounts/%v", testUUID)
+
+	mux.HandleFunc(u, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, readFixture("version-conflict.json"))
+	})
+
+	body := &AccountPatch{Data: &AccountPatchData{ID: testUUID, Type: "accounts", Version: 1}}
+
+	_, _, err := client.Accounts.UpdateAccount(ctx, body)
+
+	var conflictErr *VersionConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Expected a VersionConflictError, got %v", err)
+	}
+}
+
+func TestUpdateAccount_NoData(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	for _, body := range []*AccountPatch{nil, {}} {
+		if _, _, err := client.Accounts.UpdateAccount(ctx, body); err == nil {
+			t.Errorf("Expected an error for %+v", body)
+		}
+	}
+}
+
 func TestDeleteAccount(t *testing.T) {
 	teardown := setup()
 	defer teardown()
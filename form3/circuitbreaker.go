@@ -0,0 +1,274 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails every request with ErrCircuitOpen.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of probe requests through to
+	// check whether the API has recovered.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerSettings configures a CircuitBreaker. Zero fields take the
+// values of DefaultCircuitBreakerSettings.
+type CircuitBreakerSettings struct {
+	// FailureRatio is the ratio of failed requests at which the circuit
+	// opens, once MinRequests requests were sent within Interval.
+	FailureRatio float64
+	MinRequests  int
+
+	// Interval is the period after which the counts of a closed circuit are
+	// cleared.
+	Interval time.Duration
+
+	// OpenDuration is how long the circuit stays open before it becomes
+	// half-open.
+	OpenDuration time.Duration
+
+	// HalfOpenProbes is the number of requests let through by a half-open
+	// circuit. The circuit closes when all of them succeed, and opens again
+	// as soon as one fails.
+	HalfOpenProbes int
+
+	// IsFailure reports whether the error of a request counts as a failure.
+	// If nil, transport errors, rate limiting and server errors are failures,
+	// while client errors such as ErrNotFound and cancelled requests are not.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called after the state of the circuit changes, e.g.
+	// to raise an alert. It must not block.
+	OnStateChange func(from, to CircuitState)
+}
+
+// DefaultCircuitBreakerSettings returns settings that open the circuit for 30
+// seconds when at least half of 10 or more requests within a minute fail.
+func DefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return CircuitBreakerSettings{
+		FailureRatio:   0.5,
+		MinRequests:    10,
+		Interval:       time.Minute,
+		OpenDuration:   30 * time.Second,
+		HalfOpenProbes: 1,
+	}
+}
+
+// CircuitBreaker fails requests fast with ErrCircuitOpen while the API is
+// failing, instead of sending them.
+type CircuitBreaker struct {
+	settings CircuitBreakerSettings
+
+	mu         sync.Mutex
+	state      CircuitState
+	generation uint64
+	expiry     time.Time
+	requests   int
+	failures   int
+	probes     int
+	successes  int
+	clock      clock
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker.
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	defaults := DefaultCircuitBreakerSettings()
+
+	if settings.FailureRatio <= 0 {
+		settings.FailureRatio = defaults.FailureRatio
+	}
+	if settings.MinRequests < 1 {
+		settings.MinRequests = defaults.MinRequests
+	}
+	if settings.Interval <= 0 {
+		settings.Interval = defaults.Interval
+	}
+	if settings.OpenDuration <= 0 {
+		settings.OpenDuration = defaults.OpenDuration
+	}
+	if settings.HalfOpenProbes < 1 {
+		settings.HalfOpenProbes = defaults.HalfOpenProbes
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = isCircuitFailure
+	}
+
+	cb := &CircuitBreaker{
+		settings: settings,
+		clock:    realClock{},
+	}
+	cb.expiry = cb.clock.Now().Add(settings.Interval)
+
+	return cb
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	state := cb.state
+	from, to, changed := cb.advance(cb.clock.Now())
+	if changed {
+		state = to
+	}
+	cb.mu.Unlock()
+
+	cb.notify(from, to, changed)
+
+	return state
+}
+
+// allow reports whether a request may be sent, returning ErrCircuitOpen if
+// not. The returned generation must be passed to done.
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	cb.mu.Lock()
+	from, to, changed := cb.advance(cb.clock.Now())
+
+	var err error
+	switch {
+	case cb.state == CircuitOpen:
+		err = ErrCircuitOpen
+	case cb.state == CircuitHalfOpen && cb.probes >= cb.settings.HalfOpenProbes:
+		err = ErrCircuitOpen
+	case cb.state == CircuitHalfOpen:
+		cb.probes++
+	default:
+		cb.requests++
+	}
+
+	generation := cb.generation
+	cb.mu.Unlock()
+
+	cb.notify(from, to, changed)
+
+	return generation, err
+}
+
+// done records the outcome of a request allowed in the given generation.
+// Outcomes of requests allowed before the last state change are ignored.
+func (cb *CircuitBreaker) done(generation uint64, err error) {
+	failed := cb.settings.IsFailure(err)
+
+	cb.mu.Lock()
+
+	var (
+		from, to CircuitState
+		changed  bool
+	)
+
+	if generation == cb.generation {
+		now := cb.clock.Now()
+
+		switch cb.state {
+		case CircuitClosed:
+			if failed {
+				cb.failures++
+			}
+
+			if cb.requests >= cb.settings.MinRequests &&
+				float64(cb.failures)/float64(cb.requests) >= cb.settings.FailureRatio {
+				from, to, changed = cb.setState(CircuitOpen, now)
+			}
+		case CircuitHalfOpen:
+			if failed {
+				from, to, changed = cb.setState(CircuitOpen, now)
+				break
+			}
+
+			cb.successes++
+			if cb.successes >= cb.settings.HalfOpenProbes {
+				from, to, changed = cb.setState(CircuitClosed, now)
+			}
+		}
+	}
+
+	cb.mu.Unlock()
+
+	cb.notify(from, to, changed)
+}
+
+// advance moves an open circuit to half-open once OpenDuration has passed,
+// and clears the counts of a closed circuit every Interval.
+func (cb *CircuitBreaker) advance(now time.Time) (from, to CircuitState, changed bool) {
+	if now.Before(cb.expiry) {
+		return from, to, false
+	}
+
+	switch cb.state {
+	case CircuitOpen:
+		return cb.setState(CircuitHalfOpen, now)
+	case CircuitClosed:
+		cb.reset(now)
+	}
+
+	return from, to, false
+}
+
+func (cb *CircuitBreaker) setState(state CircuitState, now time.Time) (from, to CircuitState, changed bool) {
+	from = cb.state
+	cb.state = state
+	cb.reset(now)
+
+	if state == CircuitOpen {
+		cb.expiry = now.Add(cb.settings.OpenDuration)
+	}
+
+	return from, state, true
+}
+
+func (cb *CircuitBreaker) reset(now time.Time) {
+	cb.generation++
+	cb.requests, cb.failures = 0, 0
+	cb.probes, cb.successes = 0, 0
+	cb.expiry = now.Add(cb.settings.Interval)
+
+	if cb.state == CircuitHalfOpen {
+		cb.expiry = time.Time{}
+	}
+}
+
+func (cb *CircuitBreaker) notify(from, to CircuitState, changed bool) {
+	if changed && cb.settings.OnStateChange != nil {
+		cb.settings.OnStateChange(from, to)
+	}
+}
+
+// isCircuitFailure is the default CircuitBreakerSettings.IsFailure.
+func isCircuitFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.Status >= http.StatusInternalServerError || errResp.Status == http.StatusTooManyRequests
+	}
+
+	return true
+}
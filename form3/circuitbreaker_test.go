@@ -0,0 +1,236 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var errServer = &ErrorResponse{Status: http.StatusInternalServerError}
+
+type stateChange struct {
+	from, to CircuitState
+}
+
+func newTestCircuitBreaker(settings CircuitBreakerSettings) (*CircuitBreaker, *fakeClock, *[]stateChange) {
+	var changes []stateChange
+	settings.OnStateChange = func(from, to CircuitState) {
+		changes = append(changes, stateChange{from, to})
+	}
+
+	clk := &fakeClock{now: time.Date(2022, 10, 23, 15, 50, 0, 0, time.UTC)}
+
+	cb := NewCircuitBreaker(settings)
+	cb.clock = clk
+	cb.expiry = clk.now.Add(cb.settings.Interval)
+
+	return cb, clk, &changes
+}
+
+func call(cb *CircuitBreaker, err error) error {
+	generation, openErr := cb.allow()
+	if openErr != nil {
+		return openErr
+	}
+
+	cb.done(generation, err)
+
+	return nil
+}
+
+func TestCircuitBreaker_Opens(t *testing.T) {
+	cb, _, changes := newTestCircuitBreaker(CircuitBreakerSettings{FailureRatio: 0.5, MinRequests: 4})
+
+	_ = call(cb, nil)
+	_ = call(cb, errServer)
+	_ = call(cb, errServer)
+
+	equal(t, cb.State(), CircuitClosed)
+
+	_ = call(cb, nil)
+
+	equal(t, cb.State(), CircuitOpen)
+
+	if err := call(cb, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+
+	want := []stateChange{{CircuitClosed, CircuitOpen}}
+	if !cmp.Equal(want, *changes, cmp.AllowUnexported(stateChange{})) {
+		t.Errorf("Unexpected state changes: %v", *changes)
+	}
+}
+
+func TestCircuitBreaker_BelowThreshold(t *testing.T) {
+	cb, _, _ := newTestCircuitBreaker(CircuitBreakerSettings{FailureRatio: 0.5, MinRequests: 4})
+
+	for i := 0; i < 10; i++ {
+		err := error(nil)
+		if i%4 == 0 {
+			err = errServer
+		}
+		_ = call(cb, err)
+	}
+
+	equal(t, cb.State(), CircuitClosed)
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	cb, clk, changes := newTestCircuitBreaker(CircuitBreakerSettings{MinRequests: 1, OpenDuration: 10 * time.Second, HalfOpenProbes: 2})
+
+	_ = call(cb, errServer)
+	equal(t, cb.State(), CircuitOpen)
+
+	clk.now = clk.now.Add(10 * time.Second)
+	equal(t, cb.State(), CircuitHalfOpen)
+
+	first, err := cb.allow()
+	if err != nil {
+		t.Fatalf("Expected the first probe to be allowed, got %v", err)
+	}
+	second, err := cb.allow()
+	if err != nil {
+		t.Fatalf("Expected the second probe to be allowed, got %v", err)
+	}
+	if _, err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected the third probe to be rejected, got %v", err)
+	}
+
+	cb.done(first, nil)
+	equal(t, cb.State(), CircuitHalfOpen)
+
+	cb.done(second, nil)
+	equal(t, cb.State(), CircuitClosed)
+
+	want := []stateChange{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}
+	if !cmp.Equal(want, *changes, cmp.AllowUnexported(stateChange{})) {
+		t.Errorf("Unexpected state changes: %v", *changes)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailure(t *testing.T) {
+	cb, clk, _ := newTestCircuitBreaker(CircuitBreakerSettings{MinRequests: 1, OpenDuration: 10 * time.Second})
+
+	_ = call(cb, errServer)
+	clk.now = clk.now.Add(10 * time.Second)
+
+	_ = call(cb, errServer)
+	equal(t, cb.State(), CircuitOpen)
+
+	clk.now = clk.now.Add(9 * time.Second)
+	equal(t, cb.State(), CircuitOpen)
+}
+
+func TestCircuitBreaker_Interval(t *testing.T) {
+	cb, clk, _ := newTestCircuitBreaker(CircuitBreakerSettings{FailureRatio: 0.5, MinRequests: 2, Interval: time.Minute})
+
+	_ = call(cb, errServer)
+	clk.now = clk.now.Add(time.Minute)
+	_ = call(cb, nil)
+
+	equal(t, cb.State(), CircuitClosed)
+}
+
+func TestCircuitBreaker_StaleGeneration(t *testing.T) {
+	cb, _, _ := newTestCircuitBreaker(CircuitBreakerSettings{MinRequests: 1})
+
+	stale, _ := cb.allow()
+	_ = call(cb, errServer)
+	equal(t, cb.State(), CircuitOpen)
+
+	cb.done(stale, nil)
+	equal(t, cb.State(), CircuitOpen)
+}
+
+func TestCircuitBreaker_IsFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{&ErrorResponse{Status: http.StatusNotFound}, false},
+		{&ErrorResponse{Status: http.StatusConflict}, false},
+		{&ErrorResponse{Status: http.StatusTooManyRequests}, true},
+		{&ErrorResponse{Status: http.StatusServiceUnavailable}, true},
+		{errors.New("connection refused"), true},
+		{context.DeadlineExceeded, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.err), func(t *testing.T) {
+			equal(t, isCircuitFailure(tt.err), tt.want)
+		})
+	}
+}
+
+func TestCircuitState_String(t *testing.T) {
+	equal(t, CircuitClosed.String(), "closed")
+	equal(t, CircuitOpen.String(), "open")
+	equal(t, CircuitHalfOpen.String(), "half-open")
+	equal(t, CircuitState(9).String(), "unknown")
+}
+
+func TestSendRequest_CircuitBreaker(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	client.CircuitBreaker = NewCircuitBreaker(CircuitBreakerSettings{MinRequests: 2, FailureRatio: 1})
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	for i := 0; i < 3; i++ {
+		req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+		resp, err := client.SendRequest(req, nil)
+
+		var errResp *ErrorResponse
+		if i < 2 && !errors.As(err, &errResp) {
+			t.Errorf("Expected a server error, got %v", err)
+		}
+
+		if i == 2 && (!errors.Is(err, ErrCircuitOpen) || resp != nil) {
+			t.Errorf("Expected ErrCircuitOpen, got %v, %v", resp, err)
+		}
+	}
+
+	equal(t, calls, 2)
+}
+
+func TestSendRequest_CircuitBreakerStopsRetries(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	setupRetry(DefaultRetryPolicy())
+	client.CircuitBreaker = NewCircuitBreaker(CircuitBreakerSettings{MinRequests: 2, FailureRatio: 1})
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+	resp, err := client.SendRequest(req, nil)
+
+	equal(t, calls, 2)
+	equal(t, resp.Attempts, 2)
+	equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected the last response error, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package form3
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CountryRule validates the country specific attributes of an account, such
+// as the bank ID, bank ID code, BIC, account number and IBAN combination.
+type CountryRule interface {
+	// ValidateAttributes returns one FieldError per invalid attribute, with
+	// Field set to the attribute name, e.g. "bank_id".
+	ValidateAttributes(a *AccountAttributes) []*FieldError
+}
+
+// BankRule is a CountryRule describing the account attributes required by a
+// country, as listed in the Form3 account docs.
+//
+// Form3 API docs: https://www.api-docs.form3.tech/api/schemes/bacs/accounts/account-validation
+type BankRule struct {
+	// BankIDRequired reports whether the bank ID must be set. The bank ID must
+	// be BankIDMinLength to BankIDMaxLength characters long; a max length of 0
+	// means the bank ID is not supported.
+	BankIDRequired  bool
+	BankIDMinLength int
+	BankIDMaxLength int
+	BankIDNumeric   bool
+
+	// BankIDCode is the bank ID code of the country. If empty, the bank ID
+	// code is not supported.
+	BankIDCode         BankIDCode
+	BankIDCodeRequired bool
+
+	BicRequired bool
+
+	// The account number is optional; if set, it must be
+	// AccountNumberMinLength to AccountNumberMaxLength characters long.
+	AccountNumberMinLength int
+	AccountNumberMaxLength int
+	AccountNumberNumeric   bool
+
+	// IbanSupported reports whether the country uses IBANs.
+	IbanSupported bool
+}
+
+// ValidateAttributes validates the attributes against the rule.
+func (r *BankRule) ValidateAttributes(a *AccountAttributes) []*FieldError {
+	var errs fieldErrors
+
+	switch {
+	case a.BankID == "":
+		if r.BankIDRequired {
+			errs.add("bank_id", "is required")
+		}
+	case r.BankIDMaxLength == 0:
+		errs.add("bank_id", "is not supported")
+	default:
+		validateLength(&errs, "bank_id", a.BankID, r.BankIDMinLength, r.BankIDMaxLength, r.BankIDNumeric)
+	}
+
+	switch {
+	case a.BankIDCode == "":
+		if r.BankIDCodeRequired {
+			errs.add("bank_id_code", "is required")
+		}
+	case r.BankIDCode == "":
+		errs.add("bank_id_code", "is not supported")
+	case a.BankIDCode != r.BankIDCode:
+		errs.add("bank_id_code", "must be %q, got %q", r.BankIDCode, a.BankIDCode)
+	}
+
+	if a.Bic == "" && r.BicRequired {
+		errs.add("bic", "is required")
+	}
+
+	if a.AccountNumber != "" {
+		validateLength(&errs, "account_number", a.AccountNumber, r.AccountNumberMinLength, r.AccountNumberMaxLength, r.AccountNumberNumeric)
+	}
+
+	if a.Iban != "" && !r.IbanSupported {
+		errs.add("iban", "is not supported")
+	}
+
+	return errs
+}
+
+func validateLength(errs *fieldErrors, field, v string, min, max int, numeric bool) {
+	switch {
+	case min == max && len(v) != min:
+		errs.add(field, "must be %d characters, got %d", min, len(v))
+	case len(v) < min || len(v) > max:
+		errs.add(field, "must be %d to %d characters, got %d", min, max, len(v))
+	case numeric && !isDigits(v):
+		errs.add(field, "must only contain digits")
+	}
+}
+
+func isDigits(v string) bool {
+	for _, r := range v {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+var (
+	countryRulesMu sync.RWMutex
+	countryRules   = map[string]CountryRule{
+		"AU": &BankRule{BankIDMinLength: 6, BankIDMaxLength: 6, BankIDNumeric: true, BankIDCode: "AUBSB", BankIDCodeRequired: true, BicRequired: true, AccountNumberMinLength: 6, AccountNumberMaxLength: 10, AccountNumberNumeric: true},
+		"BE": &BankRule{BankIDRequired: true, BankIDMinLength: 3, BankIDMaxLength: 3, BankIDNumeric: true, BankIDCode: "BE", BankIDCodeRequired: true, AccountNumberMinLength: 7, AccountNumberMaxLength: 7, AccountNumberNumeric: true, IbanSupported: true},
+		"CA": &BankRule{BankIDMinLength: 9, BankIDMaxLength: 9, BankIDNumeric: true, BankIDCode: "CACPA", BicRequired: true, AccountNumberMinLength: 7, AccountNumberMaxLength: 12, AccountNumberNumeric: true},
+		"CH": &BankRule{BankIDRequired: true, BankIDMinLength: 5, BankIDMaxLength: 5, BankIDNumeric: true, BankIDCode: "CHBCC", BankIDCodeRequired: true, AccountNumberMinLength: 12, AccountNumberMaxLength: 12, IbanSupported: true},
+		"DE": &BankRule{BankIDRequired: true, BankIDMinLength: 8, BankIDMaxLength: 8, BankIDNumeric: true, BankIDCode: "DEBLZ", BankIDCodeRequired: true, AccountNumberMinLength: 7, AccountNumberMaxLength: 7, AccountNumberNumeric: true, IbanSupported: true},
+		"ES": &BankRule{BankIDRequired: true, BankIDMinLength: 8, BankIDMaxLength: 8, BankIDNumeric: true, BankIDCode: "ESNCC", BankIDCodeRequired: true, AccountNumberMinLength: 10, AccountNumberMaxLength: 10, AccountNumberNumeric: true, IbanSupported: true},
+		"FR": &BankRule{BankIDRequired: true, BankIDMinLength: 10, BankIDMaxLength: 10, BankIDNumeric: true, BankIDCode: "FR", BankIDCodeRequired: true, AccountNumberMinLength: 10, AccountNumberMaxLength: 10, IbanSupported: true},
+		"GB": &BankRule{BankIDRequired: true, BankIDMinLength: 6, BankIDMaxLength: 6, BankIDNumeric: true, BankIDCode: "GBDSC", BankIDCodeRequired: true, BicRequired: true, AccountNumberMinLength: 8, AccountNumberMaxLength: 8, AccountNumberNumeric: true, IbanSupported: true},
+		"GR": &BankRule{BankIDRequired: true, BankIDMinLength: 7, BankIDMaxLength: 7, BankIDNumeric: true, BankIDCode: "GRBIC", BankIDCodeRequired: true, AccountNumberMinLength: 16, AccountNumberMaxLength: 16, IbanSupported: true},
+		"HK": &BankRule{BankIDMinLength: 3, BankIDMaxLength: 3, BankIDNumeric: true, BankIDCode: "HKNCC", BankIDCodeRequired: true, BicRequired: true, AccountNumberMinLength: 9, AccountNumberMaxLength: 12, AccountNumberNumeric: true},
+		"IT": &BankRule{BankIDRequired: true, BankIDMinLength: 10, BankIDMaxLength: 11, BankIDNumeric: true, BankIDCode: "ITNCC", BankIDCodeRequired: true, AccountNumberMinLength: 12, AccountNumberMaxLength: 12, IbanSupported: true},
+		"LU": &BankRule{BankIDRequired: true, BankIDMinLength: 3, BankIDMaxLength: 3, BankIDNumeric: true, BankIDCode: "LULUX", BankIDCodeRequired: true, AccountNumberMinLength: 13, AccountNumberMaxLength: 13, IbanSupported: true},
+		"NL": &BankRule{BicRequired: true, AccountNumberMinLength: 10, AccountNumberMaxLength: 10, AccountNumberNumeric: true, IbanSupported: true},
+		"PL": &BankRule{BankIDRequired: true, BankIDMinLength: 8, BankIDMaxLength: 8, BankIDNumeric: true, BankIDCode: "PLKNR", BankIDCodeRequired: true, AccountNumberMinLength: 16, AccountNumberMaxLength: 16, AccountNumberNumeric: true, IbanSupported: true},
+		"PT": &BankRule{BankIDRequired: true, BankIDMinLength: 8, BankIDMaxLength: 8, BankIDNumeric: true, BankIDCode: "PTNCC", BankIDCodeRequired: true, AccountNumberMinLength: 11, AccountNumberMaxLength: 11, AccountNumberNumeric: true, IbanSupported: true},
+		"US": &BankRule{BankIDRequired: true, BankIDMinLength: 9, BankIDMaxLength: 9, BankIDNumeric: true, BankIDCode: "USABA", BankIDCodeRequired: true, BicRequired: true, AccountNumberMinLength: 6, AccountNumberMaxLength: 17, AccountNumberNumeric: true},
+	}
+)
+
+// RegisterCountryRule registers the rule used to validate accounts of a
+// country, replacing any existing rule. A nil rule removes the country's
+// rule, so its accounts are no longer validated against one.
+func RegisterCountryRule(country string, rule CountryRule) {
+	countryRulesMu.Lock()
+	defer countryRulesMu.Unlock()
+
+	if rule == nil {
+		delete(countryRules, country)
+		return
+	}
+
+	countryRules[country] = rule
+}
+
+// LookupCountryRule returns the rule registered for a country.
+func LookupCountryRule(country string) (CountryRule, bool) {
+	countryRulesMu.RLock()
+	defer countryRulesMu.RUnlock()
+
+	rule, ok := countryRules[country]
+
+	return rule, ok
+}
+
+// validateCountryRule validates the attributes against the rule registered for
+// their country, if any.
+func (a *AccountAttributes) validateCountryRule(path string, errs *fieldErrors) {
+	rule, ok := LookupCountryRule(a.Country)
+	if !ok {
+		return
+	}
+
+	for _, err := range rule.ValidateAttributes(a) {
+		errs.add(fmt.Sprintf("%v.%v", path, err.Field), "%v", err.Message)
+	}
+}
@@ -0,0 +1,204 @@
+package form3
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBankRule_ValidateAttributes(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs *AccountAttributes
+		want  []*FieldError
+	}{
+		{
+			name:  "GB valid",
+			attrs: &AccountAttributes{Country: "GB", BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", AccountNumber: "41426819", Iban: "GB16NWBK40030041426819"},
+		},
+		{
+			name:  "GB without account number",
+			attrs: &AccountAttributes{Country: "GB", BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22"},
+		},
+		{
+			name:  "GB missing fields",
+			attrs: &AccountAttributes{Country: "GB"},
+			want: []*FieldError{
+				{"bank_id", "is required"},
+				{"bank_id_code", "is required"},
+				{"bic", "is required"},
+			},
+		},
+		{
+			name:  "GB invalid sort code and account number",
+			attrs: &AccountAttributes{Country: "GB", BankID: "4003", BankIDCode: "GBDSC", Bic: "NWBKGB22", AccountNumber: "4142681A"},
+			want: []*FieldError{
+				{"bank_id", "must be 6 characters, got 4"},
+				{"account_number", "must only contain digits"},
+			},
+		},
+		{
+			name:  "GB wrong bank id code",
+			attrs: &AccountAttributes{Country: "GB", BankID: "400300", BankIDCode: "AUBSB", Bic: "NWBKGB22"},
+			want:  []*FieldError{{"bank_id_code", `must be "GBDSC", got "AUBSB"`}},
+		},
+		{
+			name:  "AU valid",
+			attrs: &AccountAttributes{Country: "AU", BankID: "013999", BankIDCode: "AUBSB", Bic: "ANZBAU3M", AccountNumber: "123456789"},
+		},
+		{
+			name:  "AU without bank id",
+			attrs: &AccountAttributes{Country: "AU", BankIDCode: "AUBSB", Bic: "ANZBAU3M"},
+		},
+		{
+			name:  "AU with iban",
+			attrs: &AccountAttributes{Country: "AU", BankIDCode: "AUBSB", Bic: "ANZBAU3M", Iban: "AU00123"},
+			want:  []*FieldError{{"iban", "is not supported"}},
+		},
+		{
+			name:  "AU account number too long",
+			attrs: &AccountAttributes{Country: "AU", BankIDCode: "AUBSB", Bic: "ANZBAU3M", AccountNumber: "12345678901"},
+			want:  []*FieldError{{"account_number", "must be 6 to 10 characters, got 11"}},
+		},
+		{
+			name:  "US valid",
+			attrs: &AccountAttributes{Country: "US", BankID: "026009593", BankIDCode: "USABA", Bic: "BOFAUS3N", AccountNumber: "1234567890"},
+		},
+		{
+			name:  "US missing routing number",
+			attrs: &AccountAttributes{Country: "US", BankIDCode: "USABA", Bic: "BOFAUS3N"},
+			want:  []*FieldError{{"bank_id", "is required"}},
+		},
+		{
+			name:  "DE valid",
+			attrs: &AccountAttributes{Country: "DE", BankID: "37040044", BankIDCode: "DEBLZ"},
+		},
+		{
+			name:  "FR valid",
+			attrs: &AccountAttributes{Country: "FR", BankID: "2004101005", BankIDCode: "FR", AccountNumber: "0500013M02"},
+		},
+		{
+			name:  "IT without account number",
+			attrs: &AccountAttributes{Country: "IT", BankID: "X0542811101", BankIDCode: "ITNCC"},
+			want:  []*FieldError{{"bank_id", "must only contain digits"}},
+		},
+		{
+			name:  "NL with bank id",
+			attrs: &AccountAttributes{Country: "NL", BankID: "ABNA", Bic: "ABNANL2A"},
+			want:  []*FieldError{{"bank_id", "is not supported"}},
+		},
+		{
+			name:  "NL with bank id code",
+			attrs: &AccountAttributes{Country: "NL", BankIDCode: "NLBIC", Bic: "ABNANL2A"},
+			want:  []*FieldError{{"bank_id_code", "is not supported"}},
+		},
+		{
+			name:  "CA optional bank id code",
+			attrs: &AccountAttributes{Country: "CA", Bic: "ROYCCAT2"},
+		},
+		{
+			name:  "HK valid",
+			attrs: &AccountAttributes{Country: "HK", BankID: "004", BankIDCode: "HKNCC", Bic: "HSBCHKHH", AccountNumber: "123456789"},
+		},
+		{
+			name:  "BE valid",
+			attrs: &AccountAttributes{Country: "BE", BankID: "539", BankIDCode: "BE", AccountNumber: "0075470"},
+		},
+		{
+			name:  "CH valid",
+			attrs: &AccountAttributes{Country: "CH", BankID: "00762", BankIDCode: "CHBCC", AccountNumber: "011623852957"},
+		},
+		{
+			name:  "ES valid",
+			attrs: &AccountAttributes{Country: "ES", BankID: "21000418", BankIDCode: "ESNCC", AccountNumber: "0200051332"},
+		},
+		{
+			name:  "GR valid",
+			attrs: &AccountAttributes{Country: "GR", BankID: "0110125", BankIDCode: "GRBIC", AccountNumber: "0000000012300695"},
+		},
+		{
+			name:  "LU valid",
+			attrs: &AccountAttributes{Country: "LU", BankID: "001", BankIDCode: "LULUX", AccountNumber: "9400644750000"},
+		},
+		{
+			name:  "PL valid",
+			attrs: &AccountAttributes{Country: "PL", BankID: "10901014", BankIDCode: "PLKNR", AccountNumber: "0000071219812874"},
+		},
+		{
+			name:  "PT valid",
+			attrs: &AccountAttributes{Country: "PT", BankID: "00020123", BankIDCode: "PTNCC", AccountNumber: "12345678901"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := LookupCountryRule(tt.attrs.Country)
+			if !ok {
+				t.Fatalf("No rule registered for %v", tt.attrs.Country)
+			}
+
+			got := rule.ValidateAttributes(tt.attrs)
+
+			if !cmp.Equal(tt.want, got, cmp.Comparer(func(a, b []*FieldError) bool {
+				return cmp.Equal(fieldErrors(a), fieldErrors(b))
+			})) {
+				t.Error(cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+type fixedRule []*FieldError
+
+func (r fixedRule) ValidateAttributes(a *AccountAttributes) []*FieldError {
+	return r
+}
+
+func TestRegisterCountryRule(t *testing.T) {
+	defer RegisterCountryRule("JP", nil)
+
+	RegisterCountryRule("JP", fixedRule{{"bank_id", "is required"}})
+
+	account := createApiResponse[*Account](testdataPath + "create-account.json")
+	account.Data.Attributes.Country = "JP"
+
+	err := account.Validate()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+
+	want := []*FieldError{{"data.attributes.bank_id", "is required"}}
+	if !cmp.Equal(want, validationErr.Errors) {
+		t.Error(cmp.Diff(want, validationErr.Errors))
+	}
+
+	RegisterCountryRule("JP", nil)
+
+	if _, ok := LookupCountryRule("JP"); ok {
+		t.Errorf("Expected the JP rule to be removed")
+	}
+
+	if err := account.Validate(); err != nil {
+		t.Errorf("Validate returned an error: %v", err)
+	}
+}
+
+func TestAccount_ValidateCountryRule(t *testing.T) {
+	account := createApiResponse[*Account](testdataPath + "create-account.json")
+	account.Data.Attributes.BankID = "40030"
+
+	err := account.Validate()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+
+	want := []*FieldError{{"data.attributes.bank_id", "must be 6 characters, got 5"}}
+	if !cmp.Equal(want, validationErr.Errors) {
+		t.Error(cmp.Diff(want, validationErr.Errors))
+	}
+}
@@ -0,0 +1,267 @@
+package form3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// EnsureAction is the action taken by EnsureAccount.
+type EnsureAction string
+
+// Actions taken by EnsureAccount.
+const (
+	// EnsureCreated means the account did not exist and was created.
+	EnsureCreated EnsureAction = "created"
+
+	// EnsureUnchanged means the account exists and matches.
+	EnsureUnchanged EnsureAction = "unchanged"
+
+	// EnsureUpdated means the account exists and was updated to match.
+	EnsureUpdated EnsureAction = "updated"
+
+	// EnsureDrifted means the account exists and differs, but was not
+	// updated.
+	EnsureDrifted EnsureAction = "drifted"
+)
+
+// EnsureResult is the result of EnsureAccount.
+type EnsureResult struct {
+	Action EnsureAction
+
+	// Account is the account after EnsureAccount returns.
+	Account *AccountResponse
+
+	// Drift lists the fields of the existing account that differed from the
+	// desired account, sorted by field.
+	Drift []*Drift
+}
+
+// Drift describes a field of an existing account that differs from the
+// desired account.
+type Drift struct {
+	// Field is the JSON path of the field, e.g. "data.attributes.bic".
+	Field string
+
+	// Want and Got are the JSON values of the field in the desired and the
+	// existing account.
+	Want json.RawMessage
+	Got  json.RawMessage
+}
+
+// EnsureOption configures EnsureAccount.
+type EnsureOption func(*ensureOptions)
+
+type ensureOptions struct {
+	reportOnly bool
+}
+
+// ReportDriftOnly makes EnsureAccount report drift instead of updating the
+// account.
+func ReportDriftOnly() EnsureOption {
+	return func(o *ensureOptions) {
+		o.reportOnly = true
+	}
+}
+
+// EnsureAccount makes sure the account exists as described by body. It
+// fetches the account by ID and creates it if it is missing. If it exists and
+// differs, the differing attributes are updated, unless ReportDriftOnly is
+// given or a field differs that cannot be updated, such as the organisation
+// ID, the type or an attribute in Extra; the drift is then reported with the
+// EnsureDrifted action.
+//
+// Attributes left empty in body, such as an account number assigned by the
+// API, are not compared. Boolean attributes are always compared. Attributes in
+// Extra are only compared if the client preserves unknown fields, see
+// WithPreserveUnknownFields, as the fetched account has none otherwise.
+func (s *AccountsService) EnsureAccount(ctx context.Context, body *Account, opts ...EnsureOption) (*EnsureResult, *Response, error) {
+	if body == nil || body.Data == nil {
+		return nil, nil, errors.New("form3: account data is required")
+	}
+
+	var o ensureOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	existing, resp, err := s.GetAccount(ctx, body.Data.ID)
+	if errors.Is(err, ErrNotFound) {
+		created, resp, err := s.CreateAccount(ctx, body)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		return &EnsureResult{Action: EnsureCreated, Account: created}, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	result := &EnsureResult{
+		Action:  EnsureUnchanged,
+		Account: existing,
+		Drift:   accountDrift(body, existing.Account, s.client.PreserveUnknownFields),
+	}
+
+	if len(result.Drift) == 0 {
+		return result, resp, nil
+	}
+
+	result.Action = EnsureDrifted
+
+	if o.reportOnly || !updatable(result.Drift) {
+		return result, resp, nil
+	}
+
+	patch, err := driftPatch(existing.Account, result.Drift)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	updated, resp, err := s.UpdateAccount(ctx, patch)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	result.Action = EnsureUpdated
+	result.Account = updated
+
+	return result, resp, nil
+}
+
+const attributesPath = "data.attributes."
+
+// accountDrift returns the fields of the existing account that differ from
+// the desired account. Attributes in Extra are only compared if extra is set.
+func accountDrift(want *Account, got *AccountResponseData, extra bool) []*Drift {
+	var drift []*Drift
+
+	fields := []struct {
+		name      string
+		want, got string
+	}{
+		{"data.organisation_id", want.Data.OrganisationID, got.OrganisationID},
+		{"data.type", want.Data.Type, got.Type},
+	}
+
+	for _, f := range fields {
+		if f.want != "" && f.want != f.got {
+			w, _ := json.Marshal(f.want)
+			g, _ := json.Marshal(f.got)
+			drift = append(drift, &Drift{Field: f.name, Want: w, Got: g})
+		}
+	}
+
+	if want.Data.Attributes == nil {
+		return drift
+	}
+
+	attrs := *want.Data.Attributes
+	if !extra {
+		attrs.Extra = nil
+	}
+
+	var wantAttrs, gotAttrs map[string]json.RawMessage
+	wb, _ := json.Marshal(&attrs)
+	_ = json.Unmarshal(wb, &wantAttrs)
+
+	if got.Attributes != nil {
+		gb, _ := json.Marshal(got.Attributes)
+		_ = json.Unmarshal(gb, &gotAttrs)
+	}
+
+	names := make([]string, 0, len(wantAttrs))
+	for name := range wantAttrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		w := wantAttrs[name]
+		if isEmptyJSON(w) {
+			continue
+		}
+
+		g, ok := gotAttrs[name]
+		if !ok {
+			g = json.RawMessage("null")
+		}
+
+		if !equalJSON(w, g) {
+			drift = append(drift, &Drift{Field: attributesPath + name, Want: w, Got: g})
+		}
+	}
+
+	return drift
+}
+
+// patchFields holds the JSON names of the attributes that can be updated.
+var patchFields = jsonFields(reflect.TypeOf(AccountAttributesPatch{}))
+
+// updatable reports whether all the drift is in attributes that can be
+// updated.
+func updatable(drift []*Drift) bool {
+	for _, d := range drift {
+		if !strings.HasPrefix(d.Field, attributesPath) {
+			return false
+		}
+
+		if _, ok := patchFields[strings.TrimPrefix(d.Field, attributesPath)]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// driftPatch returns the patch setting the drifted attributes to their
+// desired values.
+func driftPatch(got *AccountResponseData, drift []*Drift) (*AccountPatch, error) {
+	attrs := make(map[string]json.RawMessage, len(drift))
+	for _, d := range drift {
+		attrs[strings.TrimPrefix(d.Field, attributesPath)] = d.Want
+	}
+
+	b, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := new(AccountAttributesPatch)
+	if err := json.Unmarshal(b, patch); err != nil {
+		return nil, err
+	}
+
+	return &AccountPatch{
+		Data: &AccountPatchData{
+			Attributes: patch,
+			ID:         got.ID,
+			Type:       accountType,
+			Version:    int64(got.Version),
+		},
+	}, nil
+}
+
+// isEmptyJSON reports whether v is an empty string, array or object, or null.
+func isEmptyJSON(v json.RawMessage) bool {
+	switch string(bytes.TrimSpace(v)) {
+	case `""`, "[]", "{}", "null":
+		return true
+	}
+
+	return false
+}
+
+func equalJSON(a, b json.RawMessage) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(x, y)
+}
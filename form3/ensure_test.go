@@ -0,0 +1,168 @@
+package form3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+// newEnsureAccount returns the create-account.json fixture with a new ID.
+func newEnsureAccount() *Account {
+	account := createApiResponse[*Account](testdataPath + "create-account.json")
+	account.Data.ID = uuid.NewString()
+
+	return account
+}
+
+func ensureAccount(t *testing.T, account *Account, opts ...EnsureOption) *EnsureResult {
+	t.Helper()
+
+	result, _, err := client.Accounts.EnsureAccount(ctx, account, opts...)
+	if err != nil {
+		t.Fatalf("EnsureAccount returned an error: %v", err)
+	}
+
+	return result
+}
+
+func TestAccountsService_Ensure(t *testing.T) {
+	teardown := setupFake()
+	defer teardown()
+
+	account := newEnsureAccount()
+
+	result := ensureAccount(t, account)
+	equal(t, result.Action, EnsureCreated)
+	equal(t, result.Account.Account.ID, account.Data.ID)
+
+	result = ensureAccount(t, account)
+	equal(t, result.Action, EnsureUnchanged)
+	equal(t, len(result.Drift), 0)
+
+	account.Data.Attributes.Bic = "NWBKGB33"
+	account.Data.Attributes.JointAccount = true
+
+	result = ensureAccount(t, account, ReportDriftOnly())
+	equal(t, result.Action, EnsureDrifted)
+	equal(t, result.Account.Account.Attributes.Bic, "NWBKGB22")
+
+	want := []*Drift{
+		{Field: "data.attributes.bic", Want: json.RawMessage(`"NWBKGB33"`), Got: json.RawMessage(`"NWBKGB22"`)},
+		{Field: "data.attributes.joint_account", Want: json.RawMessage(`true`), Got: json.RawMessage(`false`)},
+	}
+
+	if !cmp.Equal(want, result.Drift) {
+		t.Error(cmp.Diff(want, result.Drift))
+	}
+
+	result = ensureAccount(t, account)
+	equal(t, result.Action, EnsureUpdated)
+	equal(t, len(result.Drift), 2)
+	equal(t, result.Account.Account.Attributes.Bic, "NWBKGB33")
+	equal(t, result.Account.Account.Attributes.JointAccount, true)
+
+	result = ensureAccount(t, account)
+	equal(t, result.Action, EnsureUnchanged)
+}
+
+func TestAccountsService_Ensure_OrganisationDrift(t *testing.T) {
+	teardown := setupFake()
+	defer teardown()
+
+	account := newEnsureAccount()
+	ensureAccount(t, account)
+
+	organisationID := account.Data.OrganisationID
+	account.Data.OrganisationID = uuid.NewString()
+	account.Data.Attributes.Bic = "NWBKGB33"
+
+	result := ensureAccount(t, account)
+	equal(t, result.Action, EnsureDrifted)
+	equal(t, result.Account.Account.OrganisationID, organisationID)
+	equal(t, result.Account.Account.Attributes.Bic, "NWBKGB22")
+	equal(t, len(result.Drift), 2)
+	equal(t, result.Drift[0].Field, "data.organisation_id")
+}
+
+func TestAccountsService_Ensure_Extra(t *testing.T) {
+	teardown := setupFake()
+	defer teardown()
+
+	account := newEnsureAccount()
+	ensureAccount(t, account)
+
+	account.Data.Attributes.Bic = "NWBKGB33"
+	account.Data.Attributes.Extra = map[string]json.RawMessage{"new_flag": json.RawMessage(`true`)}
+
+	// Without preserving unknown fields, the fetched account has no Extra
+	// attributes to compare with.
+	result := ensureAccount(t, account, ReportDriftOnly())
+	equal(t, result.Action, EnsureDrifted)
+
+	want := []*Drift{
+		{Field: "data.attributes.bic", Want: json.RawMessage(`"NWBKGB33"`), Got: json.RawMessage(`"NWBKGB22"`)},
+	}
+
+	if !cmp.Equal(want, result.Drift) {
+		t.Error(cmp.Diff(want, result.Drift))
+	}
+
+	client.PreserveUnknownFields = true
+
+	result = ensureAccount(t, account)
+	equal(t, result.Action, EnsureDrifted)
+	equal(t, result.Account.Account.Attributes.Bic, "NWBKGB22")
+
+	want = append(want, &Drift{Field: "data.attributes.new_flag", Want: json.RawMessage(`true`), Got: json.RawMessage(`null`)})
+
+	if !cmp.Equal(want, result.Drift) {
+		t.Error(cmp.Diff(want, result.Drift))
+	}
+}
+
+func TestAccountsService_Ensure_ExtraPreserved(t *testing.T) {
+	teardown := setupFake()
+	defer teardown()
+
+	client.PreserveUnknownFields = true
+
+	account := newEnsureAccount()
+	account.Data.Attributes.Extra = map[string]json.RawMessage{"new_flag": json.RawMessage(`true`)}
+	ensureAccount(t, account)
+
+	result := ensureAccount(t, account)
+	equal(t, result.Action, EnsureUnchanged)
+	equal(t, len(result.Drift), 0)
+}
+
+func TestAccountsService_Ensure_NilAccount(t *testing.T) {
+	teardown := setupFake()
+	defer teardown()
+
+	if _, _, err := client.Accounts.EnsureAccount(ctx, nil); err == nil {
+		t.Errorf("Expected an error for a nil account")
+	}
+}
+
+func TestAccountDrift_IgnoresEmptyFields(t *testing.T) {
+	want := newEnsureAccount()
+	want.Data.OrganisationID = ""
+	want.Data.Attributes.Name = nil
+	want.Data.Attributes.SecondaryIdentification = ""
+
+	got := &AccountResponseData{
+		ID:             want.Data.ID,
+		OrganisationID: uuid.NewString(),
+		Type:           "accounts",
+		Attributes:     newEnsureAccount().Data.Attributes,
+	}
+	got.Attributes.Name = []string{"Other Holder"}
+	got.Attributes.SecondaryIdentification = "Z9"
+	got.Attributes.AccountNumber = "41426819"
+
+	if drift := accountDrift(want, got, true); len(drift) != 0 {
+		t.Errorf("Expected no drift, got %+v", drift)
+	}
+}
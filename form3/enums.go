@@ -0,0 +1,220 @@
+package form3
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AccountClassification is the classification of an account.
+type AccountClassification string
+
+// Account classifications.
+const (
+	AccountClassificationPersonal AccountClassification = "Personal"
+	AccountClassificationBusiness AccountClassification = "Business"
+)
+
+// AccountStatus is the status of an account.
+type AccountStatus string
+
+// Account statuses.
+const (
+	AccountStatusPending   AccountStatus = "pending"
+	AccountStatusConfirmed AccountStatus = "confirmed"
+	AccountStatusFailed    AccountStatus = "failed"
+	AccountStatusClosed    AccountStatus = "closed"
+)
+
+// BankIDCode identifies the type of bank ID of an account. The API supports
+// more codes than the ones with a constant, so unknown codes in responses are
+// not rejected.
+type BankIDCode string
+
+// Bank ID codes.
+const (
+	BankIDCodeAU BankIDCode = "AUBSB"
+	BankIDCodeBE BankIDCode = "BE"
+	BankIDCodeCA BankIDCode = "CACPA"
+	BankIDCodeCH BankIDCode = "CHBCC"
+	BankIDCodeDE BankIDCode = "DEBLZ"
+	BankIDCodeES BankIDCode = "ESNCC"
+	BankIDCodeFR BankIDCode = "FR"
+	BankIDCodeGB BankIDCode = "GBDSC"
+	BankIDCodeGR BankIDCode = "GRBIC"
+	BankIDCodeHK BankIDCode = "HKNCC"
+	BankIDCodeIT BankIDCode = "ITNCC"
+	BankIDCodeLU BankIDCode = "LULUX"
+	BankIDCodePL BankIDCode = "PLKNR"
+	BankIDCodePT BankIDCode = "PTNCC"
+	BankIDCodeUS BankIDCode = "USABA"
+)
+
+// Currency is an ISO 4217 currency code. Any ISO 4217 code is valid, not
+// only the ones with a constant.
+type Currency string
+
+// Currencies of the countries supported by Form3.
+const (
+	CurrencyAUD Currency = "AUD"
+	CurrencyCAD Currency = "CAD"
+	CurrencyCHF Currency = "CHF"
+	CurrencyEUR Currency = "EUR"
+	CurrencyGBP Currency = "GBP"
+	CurrencyHKD Currency = "HKD"
+	CurrencyPLN Currency = "PLN"
+	CurrencyUSD Currency = "USD"
+)
+
+var (
+	accountClassifications = map[AccountClassification]struct{}{
+		AccountClassificationPersonal: {},
+		AccountClassificationBusiness: {},
+	}
+
+	accountStatuses = map[AccountStatus]struct{}{
+		AccountStatusPending:   {},
+		AccountStatusConfirmed: {},
+		AccountStatusFailed:    {},
+		AccountStatusClosed:    {},
+	}
+
+	bankIDCodes = map[BankIDCode]struct{}{
+		BankIDCodeAU: {}, BankIDCodeBE: {}, BankIDCodeCA: {}, BankIDCodeCH: {}, BankIDCodeDE: {},
+		BankIDCodeES: {}, BankIDCodeFR: {}, BankIDCodeGB: {}, BankIDCodeGR: {}, BankIDCodeHK: {},
+		BankIDCodeIT: {}, BankIDCodeLU: {}, BankIDCodePL: {}, BankIDCodePT: {}, BankIDCodeUS: {},
+	}
+)
+
+// UnknownValueError is returned by the Client when a response holds an
+// unknown enum value.
+type UnknownValueError struct {
+	Type  string
+	Value string
+}
+
+// Error formats the UnknownValueError.
+func (e *UnknownValueError) Error() string {
+	return fmt.Sprintf("form3: unknown %v %q", e.Type, e.Value)
+}
+
+// enum is implemented by the enum types checked in responses. The types have
+// no UnmarshalJSON methods, so json.Unmarshal keeps unknown values; they are
+// rejected by the Client after decoding, unless LenientEnums is set.
+type enum interface {
+	Valid() bool
+	enumType() string
+}
+
+// checkEnums returns an *UnknownValueError for the first unknown enum value in
+// v. Empty values are accepted, as the zero value of unset fields.
+func checkEnums(v interface{}) error {
+	return walk(reflect.ValueOf(v), func(v reflect.Value) error {
+		if v.Kind() != reflect.String {
+			return nil
+		}
+
+		if e, ok := v.Interface().(enum); ok && v.Len() > 0 && !e.Valid() {
+			return &UnknownValueError{Type: e.enumType(), Value: v.String()}
+		}
+
+		return nil
+	})
+}
+
+// walk calls visit for v and every value reachable from it through pointers,
+// interfaces, exported struct fields, slices and arrays, stopping at the first
+// error. Byte slices, such as json.RawMessage, are not traversed.
+func walk(v reflect.Value, visit func(reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walk(v.Elem(), visit)
+	}
+
+	if err := visit(v); err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := walk(v.Field(i), visit); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := walk(v.Index(i), visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns the classification as a string.
+func (c AccountClassification) String() string {
+	return string(c)
+}
+
+// Valid reports whether c is a known classification.
+func (c AccountClassification) Valid() bool {
+	_, ok := accountClassifications[c]
+	return ok
+}
+
+func (c AccountClassification) enumType() string {
+	return "account classification"
+}
+
+// String returns the status as a string.
+func (s AccountStatus) String() string {
+	return string(s)
+}
+
+// Valid reports whether s is a known status.
+func (s AccountStatus) Valid() bool {
+	_, ok := accountStatuses[s]
+	return ok
+}
+
+func (s AccountStatus) enumType() string {
+	return "account status"
+}
+
+// String returns the bank ID code as a string.
+func (c BankIDCode) String() string {
+	return string(c)
+}
+
+// Valid reports whether c is a known bank ID code.
+func (c BankIDCode) Valid() bool {
+	_, ok := bankIDCodes[c]
+	return ok
+}
+
+// String returns the currency as a string.
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Valid reports whether c is an ISO 4217 currency code.
+func (c Currency) Valid() bool {
+	_, ok := currencyCodes[string(c)]
+	return ok
+}
+
+func (c Currency) enumType() string {
+	return "currency"
+}
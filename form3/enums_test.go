@@ -0,0 +1,274 @@
+package form3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEnums_String(t *testing.T) {
+	equal(t, AccountClassificationBusiness.String(), "Business")
+	equal(t, AccountStatusConfirmed.String(), "confirmed")
+	equal(t, BankIDCodeGB.String(), "GBDSC")
+	equal(t, CurrencyEUR.String(), "EUR")
+}
+
+func TestEnums_Valid(t *testing.T) {
+	equal(t, AccountClassification("Personal").Valid(), true)
+	equal(t, AccountClassification("personal").Valid(), false)
+	equal(t, AccountStatus("closed").Valid(), true)
+	equal(t, AccountStatus("open").Valid(), false)
+	equal(t, BankIDCode("USABA").Valid(), true)
+	equal(t, BankIDCode("GBDS").Valid(), false)
+	equal(t, Currency("JPY").Valid(), true)
+	equal(t, Currency("GBX").Valid(), false)
+}
+
+func TestAccountAttributes_DecodeEnums(t *testing.T) {
+	data := `{"account_classification":"Business","bank_id_code":"DEBLZ","base_currency":"EUR","status":"pending"}`
+
+	var got AccountAttributes
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+
+	want := AccountAttributes{
+		AccountClassification: AccountClassificationBusiness,
+		BankIDCode:            BankIDCodeDE,
+		BaseCurrency:          CurrencyEUR,
+		Status:                Ptr(AccountStatusPending),
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestSendRequest_UnknownEnum(t *testing.T) {
+	tests := []struct {
+		attributes string
+		want       *UnknownValueError
+	}{
+		{`{"account_classification":"Charity"}`, &UnknownValueError{"account classification", "Charity"}},
+		{`{"base_currency":"XYZ"}`, &UnknownValueError{"currency", "XYZ"}},
+		{`{"status":"suspended"}`, &UnknownValueError{"account status", "suspended"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attributes, func(t *testing.T) {
+			teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"data":[{"id":"%v","attributes":%v}]}`, testUUID, tt.attributes)
+			})
+
+			req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+			_, err := client.SendRequest(req, new(AccountListResponse))
+
+			var unknownErr *UnknownValueError
+			if !errors.As(err, &unknownErr) {
+				t.Fatalf("Expected an UnknownValueError, got %v", err)
+			}
+
+			if !cmp.Equal(tt.want, unknownErr) {
+				t.Error(cmp.Diff(tt.want, unknownErr))
+			}
+		})
+	}
+}
+
+func TestSendRequest_LenientEnums(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	client.LenientEnums = true
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"attributes":{"account_classification":"Charity","bank_id_code":"NLBIC","base_currency":"XYZ","status":"suspended"}}}`)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	account := new(AccountResponse)
+	if _, err := client.SendRequest(req, account); err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	attrs := account.Account.Attributes
+
+	equal(t, attrs.AccountClassification, "Charity")
+	equal(t, attrs.BankIDCode, "NLBIC")
+	equal(t, attrs.BaseCurrency, "XYZ")
+	equal(t, *attrs.Status, "suspended")
+
+	b, err := json.Marshal(attrs)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+
+	var got map[string]interface{}
+	_ = json.Unmarshal(b, &got)
+
+	equal(t, got["status"].(string), "suspended")
+	equal(t, got["bank_id_code"].(string), "NLBIC")
+}
+
+func TestWithLenientEnums(t *testing.T) {
+	c, err := NewClientWithOptions(WithLenientEnums())
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	equal(t, c.LenientEnums, true)
+}
+
+func TestAccountAttributes_DecodeUnknownEnums(t *testing.T) {
+	data := `{"account_classification":"Charity","bank_id_code":"NLBIC","base_currency":"XYZ","status":"suspended"}`
+
+	// Unknown values are kept by json.Unmarshal and only rejected by the Client.
+	var got AccountAttributes
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+
+	equal(t, got.AccountClassification, "Charity")
+	equal(t, *got.Status, "suspended")
+}
+
+func TestAccountAttributes_DecodeEmptyEnums(t *testing.T) {
+	var attrs AccountAttributes
+	if err := json.Unmarshal([]byte(`{"account_classification":"","bank_id_code":null}`), &attrs); err != nil {
+		t.Errorf("Unmarshal returned an error: %v", err)
+	}
+}
+
+func TestSendRequest_UnknownBankIDCode(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":[{"id":"%v","attributes":{"bank_id_code":"NLBIC"}}]}`, testUUID)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	accounts := new(AccountListResponse)
+	if _, err := client.SendRequest(req, accounts); err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	equal(t, accounts.Accounts[0].Attributes.BankIDCode, "NLBIC")
+}
+
+func TestAccountPatch_Enums(t *testing.T) {
+	patch := &AccountAttributesPatch{
+		AccountClassification: Ptr(AccountClassificationBusiness),
+		Status:                Ptr(AccountStatusClosed),
+	}
+
+	b, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+
+	equal(t, string(b), `{"account_classification":"Business","status":"closed"}`)
+}
+
+func TestFixtures_RoundTrip(t *testing.T) {
+	tests := []struct {
+		fixture string
+		v       interface{}
+	}{
+		{"create-account.json", new(Account)},
+		{"update-account.json", new(AccountPatch)},
+		{"account-response.json", new(AccountResponse)},
+		{"account-list-response.json", new(AccountListResponse)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			fixture := readFixture(tt.fixture)
+
+			if err := json.Unmarshal([]byte(fixture), tt.v); err != nil {
+				t.Fatalf("Unmarshal returned an error: %v", err)
+			}
+
+			b, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatalf("Marshal returned an error: %v", err)
+			}
+
+			// The bytes differ from the fixture, as fields are encoded in
+			// struct order and attributes in key order, and fields without
+			// omitempty, such as iban and version, are always encoded. The
+			// documents must be equal apart from those zero values.
+			var want, got interface{}
+			_ = json.Unmarshal([]byte(fixture), &want)
+			_ = json.Unmarshal(b, &got)
+
+			if path, ok := jsonEqual(want, got, "$"); !ok {
+				t.Errorf("Re-encoded %v differs at %v:\n%s", tt.fixture, path, b)
+			}
+		})
+	}
+}
+
+// jsonEqual reports whether the decoded JSON documents want and got are equal,
+// ignoring object keys only in got with a zero value, and otherwise the path
+// of the first difference.
+func jsonEqual(want, got interface{}, path string) (string, bool) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return path, false
+		}
+
+		for k, v := range w {
+			if p, ok := jsonEqual(v, g[k], path+"."+k); !ok {
+				return p, false
+			}
+		}
+
+		for k, v := range g {
+			if _, ok := w[k]; !ok && !isZeroJSON(v) {
+				return path + "." + k, false
+			}
+		}
+
+		return "", true
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return path, false
+		}
+
+		for i := range w {
+			if p, ok := jsonEqual(w[i], g[i], path+"[]"); !ok {
+				return p, false
+			}
+		}
+
+		return "", true
+	default:
+		return path, reflect.DeepEqual(want, got)
+	}
+}
+
+// isZeroJSON reports whether the decoded JSON value v is null, false, zero or
+// an empty string.
+func isZeroJSON(v interface{}) bool {
+	switch v {
+	case nil, false, float64(0), "":
+		return true
+	}
+
+	return false
+}
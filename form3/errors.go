@@ -0,0 +1,78 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by ErrorResponse through errors.Is, so callers can
+// check the kind of failure without inspecting status codes or messages.
+var (
+	ErrNotFound     = errors.New("form3: resource not found")
+	ErrConflict     = errors.New("form3: conflict")
+	ErrValidation   = errors.New("form3: validation failed")
+	ErrUnauthorized = errors.New("form3: unauthorized")
+	ErrRateLimited  = errors.New("form3: rate limited")
+)
+
+// ErrCircuitOpen is returned without sending the request while the client's
+// CircuitBreaker is open.
+var ErrCircuitOpen = errors.New("form3: circuit breaker is open")
+
+// ErrorResponse represents an error response with a status code
+// and an error message.
+type ErrorResponse struct {
+	Status       int
+	ErrorMessage string `json:"error_message,omitempty"`
+	ErrorCode    string `json:"error_code,omitempty"`
+
+	// Body is the raw body of the error response.
+	Body []byte `json:"-"`
+
+	// Method and URL identify the request that failed.
+	Method string `json:"-"`
+	URL    string `json:"-"`
+}
+
+// Error formats the ErrorResponse.
+func (e *ErrorResponse) Error() string {
+	return fmt.Sprintf("Status: %d Message: %v", e.Status, e.ErrorMessage)
+}
+
+// Is reports whether the ErrorResponse matches one of the sentinel errors,
+// based on its status code.
+func (e *ErrorResponse) Is(target error) bool {
+	switch e.Status {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return target == ErrValidation
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	}
+
+	return false
+}
+
+// VersionConflictError is returned when an update is sent with a version
+// that does not match the current version of the account.
+type VersionConflictError struct {
+	AccountID string
+	Version   int64
+	Err       *ErrorResponse
+}
+
+// Error formats the VersionConflictError.
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version %d of account %v is not the current version: %v", e.Version, e.AccountID, e.Err)
+}
+
+// Unwrap returns the underlying ErrorResponse.
+func (e *VersionConflictError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,139 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckResponse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		fixture string
+		want    error
+		message string
+	}{
+		{
+			name:    "not found",
+			status:  http.StatusNotFound,
+			fixture: "not-found.json",
+			want:    ErrNotFound,
+			message: fmt.Sprintf("record %v does not exist", testUUID),
+		},
+		{
+			name:    "invalid uuid",
+			status:  http.StatusBadRequest,
+			fixture: "invalid-uuid.json",
+			want:    ErrValidation,
+			message: "id is not a valid uuid",
+		},
+		{
+			name:    "version conflict",
+			status:  http.StatusConflict,
+			fixture: "version-conflict.json",
+			want:    ErrConflict,
+			message: "invalid version",
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			want:   ErrUnauthorized,
+		},
+		{
+			name:   "forbidden",
+			status: http.StatusForbidden,
+			want:   ErrUnauthorized,
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			want:   ErrRateLimited,
+		},
+	}
+
+	sentinels := []error{ErrNotFound, ErrConflict, ErrValidation, ErrUnauthorized, ErrRateLimited}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teardown := setup()
+			defer teardown()
+
+			var body string
+			if tt.fixture != "" {
+				body = readFixture(tt.fixture)
+			}
+
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, body)
+			})
+
+			req, _ := client.NewRequest(ctx, http.MethodGet, "/v1/organisation/accounts/"+testUUID, nil)
+			_, err := client.SendRequest(req, nil)
+
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, sentinel, got)
+				}
+			}
+
+			var errResp *ErrorResponse
+			if !errors.As(err, &errResp) {
+				t.Fatalf("Expected an ErrorResponse, got %v", err)
+			}
+
+			want := &ErrorResponse{
+				Status:       tt.status,
+				ErrorMessage: tt.message,
+				Body:         []byte(body),
+				Method:       http.MethodGet,
+				URL:          server.URL + "/v1/organisation/accounts/" + testUUID,
+			}
+
+			if !cmp.Equal(want, errResp) {
+				t.Error(cmp.Diff(want, errResp))
+			}
+		})
+	}
+}
+
+func TestCheckResponse_ErrorCode(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error_message":"validation failure","error_code":"a7b1e0c4-9c6f-4d3a-8c9e-2f1d4b5a6c7d"}`)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodPost, "/", nil)
+	_, err := client.SendRequest(req, nil)
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("Expected an ErrorResponse, got %v", err)
+	}
+
+	equal(t, errResp.ErrorCode, "a7b1e0c4-9c6f-4d3a-8c9e-2f1d4b5a6c7d")
+	equal(t, errResp.Method, http.MethodPost)
+}
+
+func TestVersionConflictError_Is(t *testing.T) {
+	err := error(&VersionConflictError{
+		AccountID: testUUID,
+		Version:   1,
+		Err:       &ErrorResponse{Status: http.StatusConflict},
+	})
+
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected VersionConflictError to match ErrConflict")
+	}
+
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected VersionConflictError not to match ErrNotFound")
+	}
+}
@@ -0,0 +1,102 @@
+package form3
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// attributeFields holds the JSON names of the known account attributes.
+var attributeFields = jsonFields(reflect.TypeOf(AccountAttributes{}))
+
+func jsonFields(t reflect.Type) map[string]struct{} {
+	fields := make(map[string]struct{})
+
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = struct{}{}
+		}
+	}
+
+	return fields
+}
+
+// accountAttributes has the fields of AccountAttributes without its JSON
+// methods, so it can be encoded and decoded with the default behaviour.
+type accountAttributes AccountAttributes
+
+// UnmarshalJSON decodes the attributes, keeping unknown attributes in Extra.
+func (a *AccountAttributes) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, (*accountAttributes)(a)); err != nil {
+		return err
+	}
+
+	a.Extra = nil
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	for name, value := range fields {
+		if _, ok := attributeFields[name]; ok {
+			continue
+		}
+
+		if a.Extra == nil {
+			a.Extra = make(map[string]json.RawMessage)
+		}
+		a.Extra[name] = value
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the attributes, followed by the attributes in Extra
+// sorted by name. Extra attributes with the name of a known attribute are
+// ignored.
+func (a AccountAttributes) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(accountAttributes(a))
+	if err != nil || len(a.Extra) == 0 {
+		return b, err
+	}
+
+	names := make([]string, 0, len(a.Extra))
+	for name := range a.Extra {
+		if _, ok := attributeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	for i, name := range names {
+		if i > 0 || len(b) > 2 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(a.Extra[name])
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+var attributesType = reflect.TypeOf(AccountAttributes{})
+
+// dropUnknownFields clears the Extra attributes of the accounts in v.
+func dropUnknownFields(v interface{}) {
+	_ = walk(reflect.ValueOf(v), func(v reflect.Value) error {
+		if v.Type() == attributesType && v.CanSet() {
+			v.FieldByName("Extra").Set(reflect.Zero(v.FieldByName("Extra").Type()))
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package form3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const attributesWithUnknown = `{"bic":"NWBKGB22","country":"GB","new_flag":true,"new_object":{"a":[1,2]}}`
+
+func TestAccountAttributes_UnmarshalJSONUnknown(t *testing.T) {
+	var attrs AccountAttributes
+	if err := json.Unmarshal([]byte(attributesWithUnknown), &attrs); err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+
+	want := map[string]json.RawMessage{
+		"new_flag":   json.RawMessage(`true`),
+		"new_object": json.RawMessage(`{"a":[1,2]}`),
+	}
+
+	if !cmp.Equal(want, attrs.Extra) {
+		t.Error(cmp.Diff(want, attrs.Extra))
+	}
+
+	equal(t, attrs.Bic, "NWBKGB22")
+	equal(t, attrs.Country, "GB")
+}
+
+func TestAccountAttributes_MarshalJSONExtra(t *testing.T) {
+	attrs := AccountAttributes{
+		Country: "GB",
+		Extra: map[string]json.RawMessage{
+			"new_b":   json.RawMessage(`"b"`),
+			"new_a":   json.RawMessage(`1`),
+			"country": json.RawMessage(`"FR"`),
+		},
+	}
+
+	b, err := json.Marshal(attrs)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Marshal returned invalid JSON %s: %v", b, err)
+	}
+
+	equal(t, got["country"].(string), "GB")
+	equal(t, got["new_a"].(float64), 1)
+	equal(t, got["new_b"].(string), "b")
+
+	ptr, err := json.Marshal(&attrs)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+
+	equal(t, string(ptr), string(b))
+}
+
+func TestSendRequest_DropsUnknownFields(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":[{"attributes":%v},{"attributes":%v}]}`, attributesWithUnknown, attributesWithUnknown)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	accounts := new(AccountListResponse)
+	if _, err := client.SendRequest(req, accounts); err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	for _, account := range accounts.Accounts {
+		if account.Attributes.Extra != nil {
+			t.Errorf("Expected no extra attributes, got %v", account.Attributes.Extra)
+		}
+		equal(t, account.Attributes.Bic, "NWBKGB22")
+	}
+}
+
+func TestWithPreserveUnknownFields(t *testing.T) {
+	c, err := NewClientWithOptions(WithPreserveUnknownFields())
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	equal(t, c.PreserveUnknownFields, true)
+}
+
+func TestAccountAttributes_RoundTripUnknown(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	client.PreserveUnknownFields = true
+
+	mux.HandleFunc("/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4de", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4de","attributes":%v}}`, attributesWithUnknown)
+	})
+
+	account, _, err := client.Accounts.GetAccount(ctx, "ad27e265-9605-4b4b-a0e5-3003ea9cc4de")
+	if err != nil {
+		t.Fatalf("GetAccount returned an error: %v", err)
+	}
+
+	account.Account.Attributes.Bic = "NWBKGB33"
+
+	b, err := json.Marshal(account.Account.Attributes)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+
+	var want, got map[string]interface{}
+	_ = json.Unmarshal([]byte(attributesWithUnknown), &want)
+	_ = json.Unmarshal(b, &got)
+
+	want["bic"] = "NWBKGB33"
+
+	if p, ok := jsonEqual(want, got, "$"); !ok {
+		t.Errorf("Re-encoded attributes differ at %v: %s", p, b)
+	}
+}
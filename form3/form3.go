@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -22,24 +25,79 @@ type Client struct {
 	BaseUrl  *url.URL
 	client   *http.Client
 	Accounts *AccountsService
+
+	// RetryPolicy configures retries of failed requests. If nil, every
+	// request is sent exactly once.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter throttles outgoing requests. If nil, requests are not
+	// throttled.
+	RateLimiter *RateLimiter
+
+	// ValidateRequests makes CreateAccount validate the account before it is
+	// sent, returning a *ValidationError instead of calling the API.
+	ValidateRequests bool
+
+	// LenientEnums keeps unknown enum values in decoded responses, such as
+	// an account status added to the API after this package was released.
+	// By default they are rejected with an *UnknownValueError.
+	LenientEnums bool
+
+	// PreserveUnknownFields keeps account attributes not known to this
+	// package, such as attributes added to the API after it was released, in
+	// AccountAttributes.Extra of decoded responses. By default they are
+	// dropped.
+	PreserveUnknownFields bool
+
+	// RequestLogger logs every request sent to the API. If nil, requests are
+	// not logged.
+	RequestLogger *RequestLogger
+
+	// CircuitBreaker fails requests fast while the API is failing. If nil,
+	// every request is sent.
+	CircuitBreaker *CircuitBreaker
+
+	// Metrics records metrics of every request sent with SendRequest. If
+	// nil, no metrics are recorded.
+	Metrics Metrics
+
+	userAgent  string
+	middleware []Middleware
+
+	// httpClientOptions are the changes of the HTTP client queued by options.
+	httpClientOptions []func(*http.Client) error
+
+	// transportWrappers wrap the transport once the HTTP client options were
+	// applied.
+	transportWrappers []func(http.RoundTripper) http.RoundTripper
+
+	clock  clock
+	jitter func(n int64) int64
 }
 
 type service struct {
 	client *Client
 }
 
-// NewClient returns a new Form3 API client.
+// NewClient returns a new Form3 API client. The base URL is read from the
+// BASE_URL environment variable. If httpClient is nil, a new http.Client with
+// the default timeout is used; prefer NewClientWithOptions for new code.
 func NewClient(httpClient *http.Client) *Client {
 	if httpClient == nil {
-		httpClient = http.DefaultClient
-		httpClient.Timeout = defaultTimeout
+		httpClient = &http.Client{Timeout: defaultTimeout}
 	}
 
 	baseUrl, _ := url.Parse(getBaseUrl())
 
+	return newClient(httpClient, baseUrl)
+}
+
+func newClient(httpClient *http.Client, baseUrl *url.URL) *Client {
 	c := &Client{
 		BaseUrl: baseUrl,
 		client:  httpClient,
+		clock:   realClock{},
+		jitter:  rand.Int63n,
 	}
 
 	c.Accounts = &AccountsService{client: c}
@@ -75,22 +133,99 @@ func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body int
 		return nil, err
 	}
 
-	addHeaders(req)
+	c.addHeaders(req)
 
 	return req, nil
 }
 
 // SendRequest sends an API request and returns the API response.
+// Failed attempts are retried according to the client's RetryPolicy. The
+// request is sent through the middleware added with Use.
 func (c *Client) SendRequest(req *http.Request, v interface{}) (*Response, error) {
-	resp, err := c.client.Do(req)
-	if err != nil {
+	var h Handler = func(ctx context.Context, req *http.Request) (*Response, error) {
+		if ctx != req.Context() {
+			req = req.WithContext(ctx)
+		}
+
+		return c.sendRequest(req, v)
+	}
+
+	if c.Metrics != nil {
+		h = c.recordMetrics(h)
+	}
+
+	return c.handler(h)(req.Context(), req)
+}
+
+func (c *Client) sendRequest(req *http.Request, v interface{}) (*Response, error) {
+	policy := c.RetryPolicy
+
+	if policy.maxAttempts() > 1 {
+		if err := bufferBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		resp    *http.Response
+		data    []byte
+		err     error
+		attempt int
+	)
+
+	for attempt = 1; ; attempt++ {
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		// A retry stopped by an open circuit returns the previous attempt.
+		var generation uint64
+		if c.CircuitBreaker != nil {
+			g, openErr := c.CircuitBreaker.allow()
+			if openErr != nil && attempt == 1 {
+				return nil, openErr
+			}
+			if openErr != nil {
+				attempt--
+				break
+			}
+			generation = g
+		}
+
+		resp, data, err = c.send(req)
+
+		if c.CircuitBreaker != nil {
+			c.CircuitBreaker.done(generation, err)
+		}
+
+		if c.RateLimiter != nil && resp != nil && resp.Header.Get(headerRateRemaining) != "" {
+			c.RateLimiter.update(parseRate(resp, c.clock.Now()))
+		}
+
+		if attempt >= policy.maxAttempts() || !policy.retryable(req, resp, err) {
+			break
+		}
+
+		delay := policy.delay(attempt, resp, c.clock.Now(), c.jitter)
+		if sleepErr := c.clock.Sleep(req.Context(), delay); sleepErr != nil {
+			break
+		}
+
+		if err := rewindBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp == nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	response := newResponse(resp)
+	response.Attempts = attempt
+	response.Rate = parseRate(resp, c.clock.Now())
 
-	data, err := CheckResponse(resp)
 	if err != nil {
 		return response, err
 	}
@@ -101,9 +236,79 @@ func (c *Client) SendRequest(req *http.Request, v interface{}) (*Response, error
 		err = nil
 	}
 
+	if err == nil && !c.LenientEnums {
+		err = checkEnums(v)
+	}
+
+	if !c.PreserveUnknownFields {
+		dropUnknownFields(v)
+	}
+
+	if op, ok := OperationFromContext(req.Context()); ok {
+		op.identify(v)
+	}
+
 	return response, err
 }
 
+// send makes a single attempt and returns the response with its body read.
+func (c *Client) send(req *http.Request) (*http.Response, []byte, error) {
+	if c.RequestLogger == nil {
+		return c.do(req)
+	}
+
+	var reqBody []byte
+	if c.RequestLogger.LogBodies {
+		reqBody = requestBody(req)
+	}
+
+	start := c.clock.Now()
+
+	resp, data, err := c.do(req)
+
+	c.RequestLogger.log(req, reqBody, resp, data, c.clock.Now().Sub(start), err)
+
+	return resp, data, err
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, []byte, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := CheckResponse(resp)
+
+	return resp, data, err
+}
+
+// Follow fetches a link returned by the API, such as Links.Next, and decodes
+// the response into v. Relative links are resolved against BaseUrl. Links to
+// another scheme or host are rejected, so that credentials added by the
+// transport are not sent to it.
+func (c *Client) Follow(ctx context.Context, link string, v interface{}) (*Response, error) {
+	if link == "" {
+		return nil, errors.New("form3: cannot follow an empty link")
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+
+	if u = c.BaseUrl.ResolveReference(u); u.Scheme != c.BaseUrl.Scheme || u.Host != c.BaseUrl.Host {
+		return nil, fmt.Errorf("form3: cannot follow link %q outside %v://%v", link, c.BaseUrl.Scheme, c.BaseUrl.Host)
+	}
+
+	req, err := c.NewRequest(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SendRequest(req, v)
+}
+
 // CheckResponse checks the API response for errors.
 // Status codes outside the 200 range are considered errors.
 func CheckResponse(r *http.Response) ([]byte, error) {
@@ -123,27 +328,25 @@ func CheckResponse(r *http.Response) ([]byte, error) {
 	}
 
 	errResp.Status = r.StatusCode
+	errResp.Body = data
 
-	return data, errResp
-}
-
-// ErrorResponse represents an error response with a status code
-// and an error message.
-type ErrorResponse struct {
-	Status       int
-	ErrorMessage string `json:"error_message,omitempty"`
-}
+	if r.Request != nil {
+		errResp.Method = r.Request.Method
+		errResp.URL = r.Request.URL.String()
+	}
 
-// Error formats the ErrorResponse.
-func (e *ErrorResponse) Error() string {
-	return fmt.Sprintf("Status: %d Message: %v", e.Status, e.ErrorMessage)
+	return data, errResp
 }
 
-func addHeaders(req *http.Request) {
+func (c *Client) addHeaders(req *http.Request) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
-	req.Header.Set("Host", getBaseUrl())
-	req.Header.Set("Date", time.Now().Format(time.RFC1123))
+	req.Header.Set("Host", c.BaseUrl.Host)
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 }
 
 func getBaseUrl() string {
@@ -155,11 +358,45 @@ func getBaseUrl() string {
 	return u
 }
 
+// Response wraps the HTTP response returned by the API.
 type Response struct {
 	*http.Response
+
+	// Attempts is the number of attempts made to get the response.
+	Attempts int
+
+	// Rate is the rate limit state reported with the response.
+	Rate Rate
 }
 
 func newResponse(r *http.Response) *Response {
 	resp := &Response{Response: r}
 	return resp
 }
+
+// values encodes the pagination options as page[number] and page[size].
+func (o *ListOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+
+	if o.PageNumber != 0 {
+		v.Set("page[number]", strconv.Itoa(o.PageNumber))
+	}
+
+	if o.PageSize != 0 {
+		v.Set("page[size]", strconv.Itoa(o.PageSize))
+	}
+
+	return v
+}
+
+// addQuery appends the encoded URL parameters to u.
+func addQuery(u string, v url.Values) string {
+	if len(v) == 0 {
+		return u
+	}
+
+	return u + "?" + v.Encode()
+}
@@ -6,22 +6,28 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 )
 
 func TestNewClient(t *testing.T) {
+	timeout := http.DefaultClient.Timeout
+
 	c := NewClient(nil)
 
 	if baseUrl := c.BaseUrl.String(); baseUrl != getBaseUrl() {
 		t.Errorf("NewClient BaseUrl; got %v, want %v", baseUrl, defaultBaseUrl)
 	}
 
-	if client := c.Client(); client != http.DefaultClient {
-		t.Errorf("NewClient Client; got %+v, want %+v", client, http.DefaultClient)
+	if client := c.Client(); client == http.DefaultClient {
+		t.Errorf("NewClient uses http.DefaultClient")
 	}
 
+	equal(t, c.Client().Timeout, defaultTimeout)
+	equal(t, http.DefaultClient.Timeout, timeout)
+
 	httpTestClient := new(http.Client)
 	c = NewClient(httpTestClient)
 	if client := c.Client(); client != httpTestClient {
@@ -159,3 +165,54 @@ func TestSendRequest_NoContent(t *testing.T) {
 		t.Fatalf("SendRequest returned an error: %v", err)
 	}
 }
+
+func TestFollow(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Method, http.MethodGet)
+		equal(t, r.URL.Query().Get("page[number]"), "2")
+		fmt.Fprint(w, readFixture("account-list-response.json"))
+	})
+
+	links := &Links{Next: "/v1/organisation/accounts?page%5Bnumber%5D=2&page%5Bsize%5D=2"}
+
+	accounts := new(AccountListResponse)
+
+	_, err := client.Follow(ctx, links.Next, accounts)
+	if err != nil {
+		t.Fatalf("Follow returned an error: %v", err)
+	}
+
+	equal(t, len(accounts.Accounts), 2)
+	equal(t, accounts.Links.Self, "/v1/organisation/accounts?page%5Bnumber%5D=1&page%5Bsize%5D=2")
+}
+
+func TestFollow_ForeignHost(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	links := []string{
+		"https://attacker.example/v1/organisation/accounts",
+		"//attacker.example/v1/organisation/accounts",
+		strings.Replace(server.URL, "http://", "https://", 1) + "/v1/organisation/accounts",
+	}
+
+	for _, link := range links {
+		_, err := client.Follow(ctx, link, nil)
+		if err == nil {
+			t.Errorf("Expected an error following %q", link)
+		}
+	}
+}
+
+func TestFollow_EmptyLink(t *testing.T) {
+	c := NewClient(nil)
+
+	_, err := c.Follow(ctx, "", nil)
+
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
@@ -0,0 +1,464 @@
+// Package form3test provides an in-memory fake of the Form3 account API, so
+// that clients can be tested without running the accountapi, postgres and
+// vault containers.
+//
+//	srv := form3test.NewServer()
+//	defer srv.Close()
+//
+//	c, _ := form3.NewClientWithOptions(form3.WithBaseURL(srv.URL))
+//
+// The fake implements creating, fetching, listing, updating and deleting
+// accounts, and responds with the same status codes and error messages as the
+// accountapi. As there, deleting an account with the wrong version responds
+// with 404 Not Found, while updating it responds with 409 Conflict.
+package form3test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	accountsPath    = "/v1/organisation/accounts"
+	defaultPageSize = 100
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Server is a fake Form3 account API listening on a local address.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	accounts map[string]*account
+	order    []string
+
+	// Now returns the time used for created_on and modified_on.
+	Now func() time.Time
+}
+
+// account is an account as stored by the fake. Attributes are kept as
+// decoded JSON so that unknown attributes round-trip unchanged.
+type account struct {
+	Attributes     map[string]interface{} `json:"attributes"`
+	CreatedOn      time.Time              `json:"created_on"`
+	ID             string                 `json:"id"`
+	ModifiedOn     time.Time              `json:"modified_on"`
+	OrganisationID string                 `json:"organisation_id"`
+	Relationships  json.RawMessage        `json:"relationships,omitempty"`
+	Type           string                 `json:"type"`
+	Version        int64                  `json:"version"`
+}
+
+type accountRequest struct {
+	Data *struct {
+		Attributes     map[string]interface{} `json:"attributes"`
+		ID             string                 `json:"id"`
+		OrganisationID string                 `json:"organisation_id"`
+		Relationships  json.RawMessage        `json:"relationships"`
+		Type           string                 `json:"type"`
+		Version        *int64                 `json:"version"`
+	} `json:"data"`
+}
+
+type links struct {
+	First string `json:"first,omitempty"`
+	Last  string `json:"last,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Self  string `json:"self"`
+}
+
+// NewServer starts and returns a new fake Form3 account API. The caller
+// should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		accounts: make(map[string]*account),
+		Now:      time.Now,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(accountsPath, s.handleAccounts)
+	mux.HandleFunc(accountsPath+"/", s.handleAccount)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Reset deletes all accounts.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts = make(map[string]*account)
+	s.order = nil
+}
+
+// Len returns the number of stored accounts.
+func (s *Server) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.accounts)
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.list(w, r)
+	case http.MethodPost:
+		s.create(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, accountsPath+"/")
+
+	if !uuidPattern.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.fetch(w, id)
+	case http.MethodPatch:
+		s.update(w, r, id)
+	case http.MethodDelete:
+		s.delete(w, r, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	body := new(accountRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if errs := validate(body); len(errs) > 0 {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+strings.Join(errs, "\n"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.accounts[body.Data.ID]; ok {
+		writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		return
+	}
+
+	now := s.Now().UTC()
+
+	a := &account{
+		Attributes:     body.Data.Attributes,
+		CreatedOn:      now,
+		ID:             body.Data.ID,
+		ModifiedOn:     now,
+		OrganisationID: body.Data.OrganisationID,
+		Relationships:  body.Data.Relationships,
+		Type:           body.Data.Type,
+	}
+
+	s.accounts[a.ID] = a
+	s.order = append(s.order, a.ID)
+
+	writeAccount(w, http.StatusCreated, a)
+}
+
+func (s *Server) fetch(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.accounts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %v does not exist", id))
+		return
+	}
+
+	writeAccount(w, http.StatusOK, a)
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, id string) {
+	body := new(accountRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil || body.Data == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if body.Data.Version == nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.accounts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %v does not exist", id))
+		return
+	}
+
+	if *body.Data.Version != a.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	if a.Attributes == nil {
+		a.Attributes = make(map[string]interface{})
+	}
+
+	for k, v := range body.Data.Attributes {
+		a.Attributes[k] = v
+	}
+
+	a.Version++
+	a.ModifiedOn = s.Now().UTC()
+
+	writeAccount(w, http.StatusOK, a)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, id string) {
+	version, err := strconv.ParseInt(r.URL.Query().Get("version"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Like the accountapi, a version mismatch is reported as a missing
+	// account rather than a conflict.
+	a, ok := s.accounts[id]
+	if !ok || version != a.Version {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	delete(s.accounts, id)
+
+	for i, v := range s.order {
+		if v == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	size := defaultPageSize
+	if v := query.Get("page[size]"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid page size")
+			return
+		}
+		size = n
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matches []*account
+	for _, id := range s.order {
+		if a := s.accounts[id]; matchesFilters(a, query) {
+			matches = append(matches, a)
+		}
+	}
+
+	last := int(math.Ceil(float64(len(matches))/float64(size))) - 1
+	if last < 0 {
+		last = 0
+	}
+
+	number := 0
+	switch v := query.Get("page[number]"); v {
+	case "", "first":
+	case "last":
+		number = last
+	default:
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid page number")
+			return
+		}
+		number = n
+	}
+
+	data := make([]*account, 0, size)
+	for i := number * size; i < (number+1)*size && i < len(matches); i++ {
+		data = append(data, matches[i])
+	}
+
+	l := &links{
+		First: pageLink(query, "first", size),
+		Last:  pageLink(query, "last", size),
+		Self:  r.URL.RequestURI(),
+	}
+
+	if number < last {
+		l.Next = pageLink(query, strconv.Itoa(number+1), size)
+	}
+
+	if number > 0 {
+		l.Prev = pageLink(query, strconv.Itoa(number-1), size)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  data,
+		"links": l,
+	})
+}
+
+// matchesFilters reports whether the account matches every filter[...]
+// parameter of the query.
+func matchesFilters(a *account, query url.Values) bool {
+	for key, values := range query {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+
+		name := key[len("filter[") : len(key)-1]
+
+		if fmt.Sprint(a.Attributes[name]) != values[0] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func pageLink(query url.Values, number string, size int) string {
+	v := url.Values{}
+	for key, values := range query {
+		v[key] = values
+	}
+
+	v.Set("page[number]", number)
+	v.Set("page[size]", strconv.Itoa(size))
+
+	return accountsPath + "?" + v.Encode()
+}
+
+var attributePatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"account_number", regexp.MustCompile(`^[A-Z0-9]{0,64}$`)},
+	{"bank_id", regexp.MustCompile(`^[A-Z0-9]{0,16}$`)},
+	{"bank_id_code", regexp.MustCompile(`^[A-Z]{0,16}$`)},
+	{"base_currency", regexp.MustCompile(`^[A-Z]{3}$`)},
+	{"bic", regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`)},
+	{"country", regexp.MustCompile(`^[A-Z]{2}$`)},
+	{"iban", regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{0,64}$`)},
+}
+
+// validate checks a create request the way the accountapi does, returning
+// one message per failure.
+func validate(body *accountRequest) []string {
+	if body.Data == nil {
+		return []string{"data in body is required"}
+	}
+
+	var errs []string
+
+	d := body.Data
+
+	switch {
+	case d.ID == "":
+		errs = append(errs, "id in body is required")
+	case !uuidPattern.MatchString(d.ID):
+		errs = append(errs, fmt.Sprintf("id in body must be of type uuid: %q", d.ID))
+	}
+
+	switch {
+	case d.OrganisationID == "":
+		errs = append(errs, "organisation_id in body is required")
+	case !uuidPattern.MatchString(d.OrganisationID):
+		errs = append(errs, fmt.Sprintf("organisation_id in body must be of type uuid: %q", d.OrganisationID))
+	}
+
+	switch {
+	case d.Type == "":
+		errs = append(errs, "type in body is required")
+	case d.Type != "accounts":
+		errs = append(errs, "type in body should be one of [accounts]")
+	}
+
+	if d.Attributes == nil {
+		return append(errs, "attributes in body is required")
+	}
+
+	if _, ok := d.Attributes["country"]; !ok {
+		errs = append(errs, "country in body is required")
+	}
+
+	if names, ok := d.Attributes["name"].([]interface{}); !ok || len(names) == 0 {
+		errs = append(errs, "name in body is required")
+	} else if len(names) > 4 {
+		errs = append(errs, "name in body should have at most 4 items")
+	}
+
+	if c, ok := d.Attributes["account_classification"].(string); ok && c != "" && c != "Personal" && c != "Business" {
+		errs = append(errs, "account_classification in body should be one of [Personal Business]")
+	}
+
+	for _, p := range attributePatterns {
+		v, ok := d.Attributes[p.name].(string)
+		if !ok || v == "" {
+			continue
+		}
+
+		if !p.pattern.MatchString(v) {
+			errs = append(errs, fmt.Sprintf("%v in body should match '%v'", p.name, p.pattern))
+		}
+	}
+
+	sort.Strings(errs)
+
+	return errs
+}
+
+func writeAccount(w http.ResponseWriter, status int, a *account) {
+	writeJSON(w, status, map[string]interface{}{
+		"data": a,
+		"links": &links{
+			Self: accountsPath + "/" + a.ID,
+		},
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"error_message": message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
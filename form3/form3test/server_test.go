@@ -0,0 +1,222 @@
+package form3test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/froedevrolijk/form3-exercise/form3"
+	"github.com/froedevrolijk/form3-exercise/form3/form3test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func setup(t *testing.T) (*form3test.Server, *form3.Client) {
+	srv := form3test.NewServer()
+	t.Cleanup(srv.Close)
+
+	c, err := form3.NewClientWithOptions(form3.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	return srv, c
+}
+
+func newAccount(id string) *form3.Account {
+	return &form3.Account{
+		Data: &form3.AccountData{
+			Type:           "accounts",
+			ID:             id,
+			OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+			Attributes: &form3.AccountAttributes{
+				Country:      "GB",
+				BaseCurrency: "GBP",
+				BankID:       "400300",
+				BankIDCode:   "GBDSC",
+				Bic:          "NWBKGB22",
+				Name:         []string{"Samantha Holder"},
+			},
+		},
+	}
+}
+
+func TestServer_CreateAndFetch(t *testing.T) {
+	srv, c := setup(t)
+
+	created := time.Date(2022, 10, 23, 15, 50, 41, 0, time.UTC)
+	srv.Now = func() time.Time { return created }
+
+	id := uuid.NewString()
+
+	_, resp, err := c.Accounts.CreateAccount(ctx, newAccount(id))
+	assert.Nil(t, err, "expecting nil err")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	account, _, err := c.Accounts.GetAccount(ctx, id)
+	assert.Nil(t, err, "expecting nil err")
+	assert.Equal(t, id, account.Account.ID)
+	assert.Equal(t, "NWBKGB22", account.Account.Attributes.Bic)
+	assert.Equal(t, created, account.Account.CreatedOn)
+	assert.Equal(t, "/v1/organisation/accounts/"+id, account.Links.Self)
+	assert.Equal(t, 1, srv.Len())
+}
+
+func TestServer_CreateFullAccount(t *testing.T) {
+	_, c := setup(t)
+
+	account := newAccount(uuid.NewString())
+	account.Data.Attributes.PrivateIdentification = &form3.PrivateIdentification{
+		BirthDate:      "2017-07-23",
+		Identification: "13YH458762",
+	}
+	account.Data.Attributes.UserDefinedData = []*form3.UserDefinedData{{Key: "k", Value: "v"}}
+	account.Data.Relationships = &form3.AccountRelationships{
+		MasterAccount: &form3.Relationship{Data: []*form3.ResourceIdentifier{{ID: uuid.NewString(), Type: "accounts"}}},
+	}
+
+	_, _, err := c.Accounts.CreateAccount(ctx, account)
+	assert.Nil(t, err, "expecting nil err")
+
+	got, _, err := c.Accounts.GetAccount(ctx, account.Data.ID)
+	assert.Nil(t, err, "expecting nil err")
+	assert.Equal(t, account.Data.Attributes.PrivateIdentification, got.Account.Attributes.PrivateIdentification)
+	assert.Equal(t, account.Data.Attributes.UserDefinedData, got.Account.Attributes.UserDefinedData)
+	assert.Equal(t, account.Data.Relationships, got.Account.Relationships)
+}
+
+func TestServer_CreateDuplicate(t *testing.T) {
+	_, c := setup(t)
+
+	id := uuid.NewString()
+
+	_, _, _ = c.Accounts.CreateAccount(ctx, newAccount(id))
+
+	account, resp, err := c.Accounts.CreateAccount(ctx, newAccount(id))
+	assert.Nil(t, err, "expecting an identical create to succeed")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, id, account.Account.ID)
+
+	different := newAccount(id)
+	different.Data.Attributes.Bic = "NWBKGB33"
+
+	_, resp, err = c.Accounts.CreateAccount(ctx, different)
+
+	assert.True(t, errors.Is(err, form3.ErrConflict), "expecting a conflict")
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Contains(t, err.Error(), "Account cannot be created as it violates a duplicate constraint")
+}
+
+func TestServer_CreateValidation(t *testing.T) {
+	_, c := setup(t)
+
+	account := newAccount("not-a-uuid")
+	account.Data.Type = "payments"
+	account.Data.Attributes.Country = "gb"
+
+	_, _, err := c.Accounts.CreateAccount(ctx, account)
+
+	assert.True(t, errors.Is(err, form3.ErrValidation), "expecting a validation error")
+	assert.Contains(t, err.Error(), `id in body must be of type uuid: "not-a-uuid"`)
+	assert.Contains(t, err.Error(), "type in body should be one of [accounts]")
+	assert.Contains(t, err.Error(), "country in body should match '^[A-Z]{2}$'")
+}
+
+func TestServer_List(t *testing.T) {
+	_, c := setup(t)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id := uuid.NewString()
+		ids = append(ids, id)
+		_, _, _ = c.Accounts.CreateAccount(ctx, newAccount(id))
+	}
+
+	opts := &form3.ListAccountsOptions{ListOptions: form3.ListOptions{PageNumber: 1, PageSize: 2}}
+
+	accounts, _, err := c.Accounts.ListAccounts(ctx, opts)
+	assert.Nil(t, err, "expecting nil err")
+	assert.Len(t, accounts.Accounts, 2)
+	assert.Equal(t, ids[2], accounts.Accounts[0].ID)
+	assert.Equal(t, "/v1/organisation/accounts?page%5Bnumber%5D=2&page%5Bsize%5D=2", accounts.Links.Next)
+	assert.Equal(t, "/v1/organisation/accounts?page%5Bnumber%5D=0&page%5Bsize%5D=2", accounts.Links.Prev)
+
+	var all []string
+	err = c.Accounts.ForEachAccount(ctx, &form3.ListAccountsOptions{ListOptions: form3.ListOptions{PageSize: 2}}, func(a *form3.AccountResponseData) error {
+		all = append(all, a.ID)
+		return nil
+	})
+	assert.Nil(t, err, "expecting nil err")
+	assert.Equal(t, ids, all)
+}
+
+func TestServer_ListFilters(t *testing.T) {
+	_, c := setup(t)
+
+	gb := newAccount(uuid.NewString())
+	_, _, _ = c.Accounts.CreateAccount(ctx, gb)
+
+	de := newAccount(uuid.NewString())
+	de.Data.Attributes.Country = "DE"
+	_, _, _ = c.Accounts.CreateAccount(ctx, de)
+
+	accounts, _, err := c.Accounts.ListAccounts(ctx, &form3.ListAccountsOptions{Country: "DE", Bic: "NWBKGB22"})
+	assert.Nil(t, err, "expecting nil err")
+	assert.Len(t, accounts.Accounts, 1)
+	assert.Equal(t, de.Data.ID, accounts.Accounts[0].ID)
+}
+
+func TestServer_Update(t *testing.T) {
+	_, c := setup(t)
+
+	id := uuid.NewString()
+	_, _, _ = c.Accounts.CreateAccount(ctx, newAccount(id))
+
+	patch := &form3.AccountPatch{
+		Data: &form3.AccountPatchData{
+			ID:      id,
+			Type:    "accounts",
+			Version: 0,
+			Attributes: &form3.AccountAttributesPatch{
+				Name: form3.Strings("Samantha Holder-Smith"),
+			},
+		},
+	}
+
+	account, _, err := c.Accounts.UpdateAccount(ctx, patch)
+	assert.Nil(t, err, "expecting nil err")
+	assert.Equal(t, 1, account.Account.Version)
+	assert.Equal(t, []string{"Samantha Holder-Smith"}, account.Account.Attributes.Name)
+	assert.Equal(t, "NWBKGB22", account.Account.Attributes.Bic)
+
+	_, _, err = c.Accounts.UpdateAccount(ctx, patch)
+
+	var conflictErr *form3.VersionConflictError
+	assert.True(t, errors.As(err, &conflictErr), "expecting a version conflict")
+}
+
+func TestServer_Delete(t *testing.T) {
+	srv, c := setup(t)
+
+	id := uuid.NewString()
+	_, _, _ = c.Accounts.CreateAccount(ctx, newAccount(id))
+
+	resp, err := c.Accounts.DeleteAccount(ctx, &form3.DeleteOptions{AccountID: id, Version: 1})
+	assert.True(t, errors.Is(err, form3.ErrNotFound), "expecting not found")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, 1, srv.Len())
+
+	resp, err = c.Accounts.DeleteAccount(ctx, &form3.DeleteOptions{AccountID: id, Version: 0})
+	assert.Nil(t, err, "expecting nil err")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 0, srv.Len())
+
+	_, _, err = c.Accounts.GetAccount(ctx, id)
+	assert.True(t, errors.Is(err, form3.ErrNotFound), "expecting not found")
+	assert.Contains(t, err.Error(), fmt.Sprintf("record %v does not exist", id))
+}
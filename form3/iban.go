@@ -0,0 +1,309 @@
+package form3
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Iban is a parsed International Bank Account Number.
+type Iban struct {
+	Country     string
+	CheckDigits string
+
+	// Bban is the country specific Basic Bank Account Number.
+	Bban string
+}
+
+// IbanError is returned when an IBAN is invalid. It matches ErrValidation
+// through errors.Is.
+type IbanError struct {
+	Iban   string
+	Reason string
+}
+
+// Error formats the IbanError.
+func (e *IbanError) Error() string {
+	return fmt.Sprintf("form3: invalid IBAN %q: %v", e.Iban, e.Reason)
+}
+
+// Is reports whether target is ErrValidation.
+func (e *IbanError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ibanFormat describes the IBAN of a country, as listed in the SWIFT IBAN
+// registry.
+type ibanFormat struct {
+	length int
+
+	// bban is the BBAN structure in registry notation, e.g. "4!a6!n8!n".
+	bban string
+
+	// bicBankCode reports whether the BBAN starts with the first four
+	// characters of the BIC rather than the bank ID.
+	bicBankCode bool
+
+	// nationalCheck computes the national check digits appended to the bank
+	// ID and account number, if the country has any.
+	nationalCheck func(bban string) string
+
+	pattern *regexp.Regexp
+}
+
+var ibanFormats = map[string]*ibanFormat{
+	"AD": {length: 24, bban: "4!n4!n12!c"},
+	"AE": {length: 23, bban: "3!n16!n"},
+	"AL": {length: 28, bban: "8!n16!c"},
+	"AT": {length: 20, bban: "5!n11!n"},
+	"AZ": {length: 28, bban: "4!a20!c"},
+	"BA": {length: 20, bban: "3!n3!n8!n2!n"},
+	"BE": {length: 16, bban: "3!n7!n2!n", nationalCheck: belgianCheck},
+	"BG": {length: 22, bban: "4!a4!n2!n8!c"},
+	"BH": {length: 22, bban: "4!a14!c"},
+	"BR": {length: 29, bban: "8!n5!n10!n1!a1!c"},
+	"CH": {length: 21, bban: "5!n12!c"},
+	"CR": {length: 22, bban: "4!n14!n"},
+	"CY": {length: 28, bban: "3!n5!n16!c"},
+	"CZ": {length: 24, bban: "4!n6!n10!n"},
+	"DE": {length: 22, bban: "8!n10!n"},
+	"DK": {length: 18, bban: "4!n9!n1!n"},
+	"DO": {length: 28, bban: "4!c20!n"},
+	"EE": {length: 20, bban: "2!n2!n11!n1!n"},
+	"ES": {length: 24, bban: "4!n4!n1!n1!n10!n"},
+	"FI": {length: 18, bban: "3!n11!n"},
+	"FO": {length: 18, bban: "4!n9!n1!n"},
+	"FR": {length: 27, bban: "5!n5!n11!c2!n"},
+	"GB": {length: 22, bban: "4!a6!n8!n", bicBankCode: true},
+	"GE": {length: 22, bban: "2!a16!n"},
+	"GI": {length: 23, bban: "4!a15!c"},
+	"GL": {length: 18, bban: "4!n9!n1!n"},
+	"GR": {length: 27, bban: "3!n4!n16!c"},
+	"GT": {length: 28, bban: "4!c20!c"},
+	"HR": {length: 21, bban: "7!n10!n"},
+	"HU": {length: 28, bban: "3!n4!n1!n15!n1!n"},
+	"IE": {length: 22, bban: "4!a6!n8!n", bicBankCode: true},
+	"IL": {length: 23, bban: "3!n3!n13!n"},
+	"IS": {length: 26, bban: "4!n2!n6!n10!n"},
+	"IT": {length: 27, bban: "1!a5!n5!n12!c"},
+	"JO": {length: 30, bban: "4!a4!n18!c"},
+	"KW": {length: 30, bban: "4!a22!c"},
+	"KZ": {length: 20, bban: "3!n13!c"},
+	"LB": {length: 28, bban: "4!n20!c"},
+	"LI": {length: 21, bban: "5!n12!c"},
+	"LT": {length: 20, bban: "5!n11!n"},
+	"LU": {length: 20, bban: "3!n13!c"},
+	"LV": {length: 21, bban: "4!a13!c"},
+	"MC": {length: 27, bban: "5!n5!n11!c2!n"},
+	"MD": {length: 24, bban: "2!c18!c"},
+	"ME": {length: 22, bban: "3!n13!n2!n"},
+	"MK": {length: 19, bban: "3!n10!c2!n"},
+	"MR": {length: 27, bban: "5!n5!n11!n2!n"},
+	"MT": {length: 31, bban: "4!a5!n18!c"},
+	"MU": {length: 30, bban: "4!a2!n2!n12!n3!n3!a"},
+	"NL": {length: 18, bban: "4!a10!n", bicBankCode: true},
+	"NO": {length: 15, bban: "4!n6!n1!n"},
+	"PK": {length: 24, bban: "4!a16!c"},
+	"PL": {length: 28, bban: "8!n16!n"},
+	"PS": {length: 29, bban: "4!a21!c"},
+	"PT": {length: 25, bban: "4!n4!n11!n2!n"},
+	"QA": {length: 29, bban: "4!a21!c"},
+	"RO": {length: 24, bban: "4!a16!c"},
+	"RS": {length: 22, bban: "3!n13!n2!n"},
+	"SA": {length: 24, bban: "2!n18!c"},
+	"SE": {length: 24, bban: "3!n16!n1!n"},
+	"SI": {length: 19, bban: "5!n8!n2!n"},
+	"SK": {length: 24, bban: "4!n6!n10!n"},
+	"SM": {length: 27, bban: "1!a5!n5!n12!c"},
+	"TN": {length: 24, bban: "2!n3!n13!n2!n"},
+	"TR": {length: 26, bban: "5!n1!n16!c"},
+	"UA": {length: 29, bban: "6!n19!c"},
+	"VG": {length: 24, bban: "4!a16!n"},
+	"XK": {length: 20, bban: "4!n10!n2!n"},
+}
+
+var bbanPart = regexp.MustCompile(`(\d+)!([anc])`)
+
+func init() {
+	for _, f := range ibanFormats {
+		f.pattern = bbanPattern(f.bban)
+	}
+}
+
+// bbanPattern converts a BBAN structure in registry notation to a regexp.
+func bbanPattern(structure string) *regexp.Regexp {
+	classes := map[string]string{"a": "[A-Z]", "n": "[0-9]", "c": "[A-Z0-9]"}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for _, m := range bbanPart.FindAllStringSubmatch(structure, -1) {
+		fmt.Fprintf(&b, "%v{%v}", classes[m[2]], m[1])
+	}
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
+
+// ParseIban parses an IBAN in electronic or print format, e.g.
+// "GB33BUKB20201555555555" or "GB33 BUKB 2020 1555 5555 55". It checks the
+// country, length, BBAN structure and mod-97 check digits, and returns an
+// *IbanError if any of them is invalid.
+func ParseIban(s string) (*Iban, error) {
+	v := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+
+	if len(v) < 4 {
+		return nil, &IbanError{Iban: s, Reason: "too short"}
+	}
+
+	f, ok := ibanFormats[v[:2]]
+	if !ok {
+		return nil, &IbanError{Iban: s, Reason: fmt.Sprintf("country %q does not use IBANs", v[:2])}
+	}
+
+	if len(v) != f.length {
+		return nil, &IbanError{Iban: s, Reason: fmt.Sprintf("must be %d characters for %v, got %d", f.length, v[:2], len(v))}
+	}
+
+	if !isDigits(v[2:4]) {
+		return nil, &IbanError{Iban: s, Reason: "check digits must be numeric"}
+	}
+
+	if !f.pattern.MatchString(v[4:]) {
+		return nil, &IbanError{Iban: s, Reason: fmt.Sprintf("BBAN does not match the %v structure %v", v[:2], f.bban)}
+	}
+
+	if mod97(v[4:]+v[:4]) != 1 {
+		return nil, &IbanError{Iban: s, Reason: "invalid check digits"}
+	}
+
+	return &Iban{Country: v[:2], CheckDigits: v[2:4], Bban: v[4:]}, nil
+}
+
+// GenerateIban generates the IBAN of an account from its country, bank ID and
+// account number. The account number is padded with leading zeros to fill
+// the BBAN, and national check digits are added where the country has a
+// well-known algorithm for them (BE). Otherwise the account number must
+// include them.
+//
+// For GB, IE and NL the BBAN starts with the first four characters of the
+// BIC, which must be prepended to the bank ID, e.g. "NWBK400300". Use
+// AccountAttributes.GenerateIban to do this from the BIC.
+func GenerateIban(country, bankID, accountNumber string) (*Iban, error) {
+	country = strings.ToUpper(country)
+
+	f, ok := ibanFormats[country]
+	if !ok {
+		return nil, &IbanError{Iban: country, Reason: fmt.Sprintf("country %q does not use IBANs", country)}
+	}
+
+	size := f.length - 4
+	if f.nationalCheck != nil {
+		size -= 2
+	}
+
+	pad := size - len(bankID) - len(accountNumber)
+	if pad < 0 {
+		return nil, &IbanError{Iban: country + bankID + accountNumber, Reason: fmt.Sprintf("bank ID and account number must be at most %d characters for %v", size, country)}
+	}
+
+	bban := strings.ToUpper(bankID + strings.Repeat("0", pad) + accountNumber)
+	if f.nationalCheck != nil {
+		bban += f.nationalCheck(bban)
+	}
+
+	if !f.pattern.MatchString(bban) {
+		return nil, &IbanError{Iban: country + bban, Reason: fmt.Sprintf("BBAN does not match the %v structure %v", country, f.bban)}
+	}
+
+	check := 98 - mod97(bban+country+"00")
+
+	return &Iban{Country: country, CheckDigits: fmt.Sprintf("%02d", check), Bban: bban}, nil
+}
+
+// GenerateIban generates the IBAN of the account from its country, bank ID,
+// BIC and account number.
+func (a *AccountAttributes) GenerateIban() (*Iban, error) {
+	bankID := a.BankID
+
+	if f, ok := ibanFormats[a.Country]; ok && f.bicBankCode {
+		if len(a.Bic) < 4 {
+			return nil, &IbanError{Iban: a.Country, Reason: fmt.Sprintf("a BIC is required to generate an IBAN for %v", a.Country)}
+		}
+		bankID = a.Bic[:4] + bankID
+	}
+
+	return GenerateIban(a.Country, bankID, a.AccountNumber)
+}
+
+// String returns the IBAN in electronic format, e.g. "GB33BUKB20201555555555".
+func (i *Iban) String() string {
+	return i.Country + i.CheckDigits + i.Bban
+}
+
+// Format returns the IBAN in print format, in groups of four characters,
+// e.g. "GB33 BUKB 2020 1555 5555 55".
+func (i *Iban) Format() string {
+	v := i.String()
+
+	var b strings.Builder
+	for n := 0; n < len(v); n += 4 {
+		if n > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(v[n:minInt(n+4, len(v))])
+	}
+
+	return b.String()
+}
+
+// mod97 computes the ISO 7064 MOD 97-10 remainder of v, with letters
+// converted to numbers (A=10, ..., Z=35).
+func mod97(v string) int {
+	r := 0
+	for _, c := range v {
+		switch {
+		case c >= '0' && c <= '9':
+			r = (r*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			r = (r*100 + int(c-'A') + 10) % 97
+		}
+	}
+
+	return r
+}
+
+// belgianCheck computes the check digits of a Belgian account number: the
+// first ten digits mod 97, with 0 replaced by 97.
+func belgianCheck(bban string) string {
+	n, _ := strconv.Atoi(bban)
+
+	check := n % 97
+	if check == 0 {
+		check = 97
+	}
+
+	return fmt.Sprintf("%02d", check)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// validateIban validates the IBAN of the account, and that it matches the
+// account's country.
+func (a *AccountAttributes) validateIban(path string, errs *fieldErrors) {
+	iban, err := ParseIban(a.Iban)
+	if err != nil {
+		errs.add(path+".iban", "%v", err.(*IbanError).Reason)
+		return
+	}
+
+	if a.Country != "" && iban.Country != a.Country {
+		errs.add(path+".iban", "country %q does not match the account country %q", iban.Country, a.Country)
+	}
+}
@@ -0,0 +1,214 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseIban(t *testing.T) {
+	valid := []string{
+		"GB33BUKB20201555555555",
+		"GB29 NWBK 6016 1331 9268 19",
+		"gb29nwbk60161331926819",
+		"BE68539007547034",
+		"CH9300762011623852957",
+		"DE89370400440532013000",
+		"ES9121000418450200051332",
+		"FR1420041010050500013M02606",
+		"GR1601101250000000012300695",
+		"IT60X0542811101000000123456",
+		"LU280019400644750000",
+		"MT84MALT011000012345MTLCAST001S",
+		"NL91ABNA0417164300",
+		"NO9386011117947",
+		"PL61109010140000071219812874",
+		"PT50000201231234567890154",
+	}
+
+	for _, v := range valid {
+		t.Run(v, func(t *testing.T) {
+			if _, err := ParseIban(v); err != nil {
+				t.Errorf("ParseIban returned an error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseIban_Parts(t *testing.T) {
+	iban, err := ParseIban("GB29 NWBK 6016 1331 9268 19")
+	if err != nil {
+		t.Fatalf("ParseIban returned an error: %v", err)
+	}
+
+	want := &Iban{Country: "GB", CheckDigits: "29", Bban: "NWBK60161331926819"}
+	if !cmp.Equal(want, iban) {
+		t.Error(cmp.Diff(want, iban))
+	}
+
+	equal(t, iban.String(), "GB29NWBK60161331926819")
+	equal(t, iban.Format(), "GB29 NWBK 6016 1331 9268 19")
+}
+
+func TestParseIban_Invalid(t *testing.T) {
+	tests := []struct {
+		iban   string
+		reason string
+	}{
+		{"GB", "too short"},
+		{"US64SVBKUS6S3300958879", `country "US" does not use IBANs`},
+		{"GB29NWBK6016133192681", "must be 22 characters for GB, got 21"},
+		{"GBXXNWBK60161331926819", "check digits must be numeric"},
+		{"GB29NWBK6016133192681X", "BBAN does not match the GB structure 4!a6!n8!n"},
+		{"GB28NWBK60161331926819", "invalid check digits"},
+		{"DE89370400440532013001", "invalid check digits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.iban, func(t *testing.T) {
+			_, err := ParseIban(tt.iban)
+
+			var ibanErr *IbanError
+			if !errors.As(err, &ibanErr) {
+				t.Fatalf("Expected an IbanError, got %v", err)
+			}
+
+			equal(t, ibanErr.Reason, tt.reason)
+
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected IbanError to match ErrValidation")
+			}
+		})
+	}
+}
+
+func TestGenerateIban(t *testing.T) {
+	tests := []struct {
+		country       string
+		bankID        string
+		accountNumber string
+		want          string
+	}{
+		{"GB", "NWBK601613", "31926819", "GB29NWBK60161331926819"},
+		{"DE", "37040044", "532013000", "DE89370400440532013000"},
+		{"BE", "539", "0075470", "BE68539007547034"},
+		{"NL", "ABNA", "417164300", "NL91ABNA0417164300"},
+		{"FR", "2004101005", "0500013M02606", "FR1420041010050500013M02606"},
+		{"ES", "21000418", "450200051332", "ES9121000418450200051332"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			iban, err := GenerateIban(tt.country, tt.bankID, tt.accountNumber)
+			if err != nil {
+				t.Fatalf("GenerateIban returned an error: %v", err)
+			}
+
+			equal(t, iban.String(), tt.want)
+		})
+	}
+}
+
+func TestGenerateIban_Invalid(t *testing.T) {
+	tests := []struct {
+		country       string
+		bankID        string
+		accountNumber string
+		reason        string
+	}{
+		{"US", "026009593", "1234567890", `country "US" does not use IBANs`},
+		{"DE", "37040044", "05320130001", "bank ID and account number must be at most 18 characters for DE"},
+		{"GB", "400300", "31926819", "BBAN does not match the GB structure 4!a6!n8!n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.country, func(t *testing.T) {
+			_, err := GenerateIban(tt.country, tt.bankID, tt.accountNumber)
+
+			var ibanErr *IbanError
+			if !errors.As(err, &ibanErr) {
+				t.Fatalf("Expected an IbanError, got %v", err)
+			}
+
+			equal(t, ibanErr.Reason, tt.reason)
+		})
+	}
+}
+
+func TestAccountAttributes_GenerateIban(t *testing.T) {
+	attrs := &AccountAttributes{Country: "GB", BankID: "601613", Bic: "NWBKGB22", AccountNumber: "31926819"}
+
+	iban, err := attrs.GenerateIban()
+	if err != nil {
+		t.Fatalf("GenerateIban returned an error: %v", err)
+	}
+
+	equal(t, iban.String(), "GB29NWBK60161331926819")
+
+	attrs.Bic = ""
+
+	if _, err := attrs.GenerateIban(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestIbanFormats(t *testing.T) {
+	for country, f := range ibanFormats {
+		length := 4
+		for _, m := range bbanPart.FindAllStringSubmatch(f.bban, -1) {
+			var n int
+			fmt.Sscan(m[1], &n)
+			length += n
+		}
+
+		if length != f.length {
+			t.Errorf("%v: BBAN structure %v gives length %d, want %d", country, f.bban, length, f.length)
+		}
+	}
+}
+
+func TestAccount_ValidateIban(t *testing.T) {
+	tests := []struct {
+		name string
+		iban string
+		want []*FieldError
+	}{
+		{
+			name: "invalid check digits",
+			iban: "GB28NWBK60161331926819",
+			want: []*FieldError{{"data.attributes.iban", "invalid check digits"}},
+		},
+		{
+			name: "country mismatch",
+			iban: "DE89370400440532013000",
+			want: []*FieldError{{"data.attributes.iban", `country "DE" does not match the account country "GB"`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := createApiResponse[*Account](testdataPath + "create-account.json")
+			account.Data.Attributes.Iban = tt.iban
+
+			err := account.Validate()
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+
+			if !cmp.Equal(tt.want, validationErr.Errors) {
+				t.Error(cmp.Diff(tt.want, validationErr.Errors))
+			}
+		})
+	}
+
+	account := createApiResponse[*Account](testdataPath + "create-account.json")
+	account.Data.Attributes.Iban = "GB16NWBK40030041426819"
+
+	if err := account.Validate(); err != nil {
+		t.Errorf("Validate returned an error: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx with the Idempotency-Key sent by
+// CreateAccount. Use the same key when retrying a create whose outcome is
+// unknown. Without a key, CreateAccount generates one per call.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// idempotencyKeyFromContext returns the key set with WithIdempotencyKey, or a
+// new random key.
+func idempotencyKeyFromContext(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKey{}).(string); ok && key != "" {
+		return key
+	}
+
+	return uuid.NewString()
+}
+
+// sameAccount reports whether the existing account is the account that was
+// sent to be created. Attributes set by the API but not sent, such as the
+// status, are ignored.
+func sameAccount(sent *Account, existing *AccountResponseData) bool {
+	if sent == nil || sent.Data == nil || existing == nil {
+		return false
+	}
+
+	if sent.Data.ID != existing.ID ||
+		sent.Data.OrganisationID != existing.OrganisationID ||
+		sent.Data.Type != existing.Type {
+		return false
+	}
+
+	return sameJSON(sent.Data.Attributes, existing.Attributes)
+}
+
+// sameJSON reports whether every field of the JSON encoding of sent has the
+// same value in the JSON encoding of existing. Fields left empty in sent, such
+// as an IBAN assigned by the API, are ignored, as by EnsureAccount.
+func sameJSON(sent, existing interface{}) bool {
+	var s, e map[string]json.RawMessage
+
+	if !decodeJSONObject(sent, &s) || !decodeJSONObject(existing, &e) {
+		return false
+	}
+
+	for k, v := range s {
+		if !isEmptyJSON(v) && !equalJSON(v, e[k]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func decodeJSONObject(v interface{}, m *map[string]json.RawMessage) bool {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(b, m) == nil
+}
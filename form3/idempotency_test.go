@@ -0,0 +1,144 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateAccount_IdempotencyKey(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var keys []string
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, readFixture("account-response.json"))
+	})
+
+	body := createApiResponse[*Account](testdataPath + "create-account.json")
+
+	_, _, _ = client.Accounts.CreateAccount(ctx, body)
+	_, _, _ = client.Accounts.CreateAccount(ctx, body)
+	_, _, _ = client.Accounts.CreateAccount(WithIdempotencyKey(ctx, "create-1"), body)
+
+	if _, err := uuid.Parse(keys[0]); err != nil {
+		t.Errorf("Expected a generated UUID key, got %q", keys[0])
+	}
+
+	if keys[0] == keys[1] {
+		t.Errorf("Expected a new key per create, got %q twice", keys[0])
+	}
+
+	equal(t, keys[2], "create-1")
+}
+
+func TestCreateAccount_RetriesWithSameKey(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	setupRetry(DefaultRetryPolicy())
+
+	var keys []string
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, readFixture("account-response.json"))
+	})
+
+	body := createApiResponse[*Account](testdataPath + "create-account.json")
+
+	_, resp, err := client.Accounts.CreateAccount(ctx, body)
+	if err != nil {
+		t.Fatalf("CreateAccount returned an error: %v", err)
+	}
+
+	equal(t, resp.Attempts, 2)
+	equal(t, keys[0], keys[1])
+}
+
+func TestCreateAccount_Duplicate(t *testing.T) {
+	// filled is the fetched account with attributes assigned by the API.
+	filled := strings.Replace(readFixture("account-response.json"), `"bank_id": "400300",`,
+		`"account_number": "41426819", "bank_id": "400300", "iban": "GB16NWBK40030041426819",`, 1)
+
+	tests := []struct {
+		name     string
+		modify   func(a *Account)
+		get      int
+		existing string
+		wantErr  bool
+	}{
+		{name: "same account", modify: func(a *Account) {}, get: http.StatusOK},
+		{name: "attributes filled by the API", modify: func(a *Account) {}, get: http.StatusOK, existing: filled},
+		{name: "different attributes", modify: func(a *Account) { a.Data.Attributes.Bic = "NWBKGB33" }, get: http.StatusOK, wantErr: true},
+		{name: "different organisation", modify: func(a *Account) { a.Data.OrganisationID = testUUID }, get: http.StatusOK, wantErr: true},
+		{name: "fetch fails", modify: func(a *Account) {}, get: http.StatusNotFound, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teardown := setup()
+			defer teardown()
+
+			existing := tt.existing
+			if existing == "" {
+				existing = readFixture("account-response.json")
+			}
+
+			mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusConflict)
+				fmt.Fprint(w, `{"error_message":"Account cannot be created as it violates a duplicate constraint"}`)
+			})
+			mux.HandleFunc("/v1/organisation/accounts/"+testUUID, func(w http.ResponseWriter, r *http.Request) {
+				equal(t, r.Method, http.MethodGet)
+				w.WriteHeader(tt.get)
+				if tt.get == http.StatusOK {
+					fmt.Fprint(w, existing)
+				}
+			})
+
+			body := createApiResponse[*Account](testdataPath + "create-account.json")
+			tt.modify(body)
+
+			account, resp, err := client.Accounts.CreateAccount(ctx, body)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrConflict) {
+					t.Errorf("Expected ErrConflict, got %v", err)
+				}
+				equal(t, resp.StatusCode, http.StatusConflict)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateAccount returned an error: %v", err)
+			}
+
+			equal(t, resp.StatusCode, http.StatusOK)
+			equal(t, account.Account.ID, testUUID)
+		})
+	}
+}
+
+func TestSameJSON(t *testing.T) {
+	sent := &AccountAttributes{Country: "GB", Name: []string{"Samantha Holder"}}
+
+	existing := &AccountAttributes{Country: "GB", Name: []string{"Samantha Holder"}, Status: Ptr(AccountStatusConfirmed)}
+	equal(t, sameJSON(sent, existing), true)
+
+	existing.Iban = "GB16NWBK40030041426819"
+	equal(t, sameJSON(sent, existing), true)
+
+	existing.Name = []string{"Sam Holder"}
+	equal(t, sameJSON(sent, existing), false)
+}
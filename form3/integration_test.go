@@ -37,7 +37,7 @@ func TestAccountsService_Create(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 
 	want := createApiResponse[*AccountResponse](testdataPath + "account-response.json")
-	if !cmp.Equal(want, account, cmpopts.IgnoreFields(AccountResponse{}, "Account.CreatedOn", "Account.ModifiedOn", "Account.ID")) {
+	if !cmp.Equal(want, account, cmpopts.IgnoreFields(AccountResponse{}, "Account.CreatedOn", "Account.ModifiedOn", "Account.ID", "Links.Self")) {
 		t.Error(cmp.Diff(want, account))
 	}
 }
@@ -99,7 +99,7 @@ func TestAccountsService_Get(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	want := createApiResponse[*AccountResponse](testdataPath + "account-response.json")
-	if !cmp.Equal(want, account, cmpopts.IgnoreFields(AccountResponse{}, "Account.CreatedOn", "Account.ModifiedOn", "Account.ID")) {
+	if !cmp.Equal(want, account, cmpopts.IgnoreFields(AccountResponse{}, "Account.CreatedOn", "Account.ModifiedOn", "Account.ID", "Links.Self")) {
 		t.Error(cmp.Diff(want, account))
 	}
 }
@@ -120,6 +120,27 @@ func TestAccountsService_Get_InvalidUUID(t *testing.T) {
 	assert.Contains(t, err.Error(), "id is not a valid uuid")
 }
 
+func TestAccountsService_List(t *testing.T) {
+	fixture := readFixture("create-account.json")
+
+	accountData := new(Account)
+
+	err := json.Unmarshal([]byte(fixture), accountData)
+	if err != nil {
+		panic(err)
+	}
+
+	accountData.Data.ID = uuid.NewString()
+
+	_, _, _ = c.Accounts.CreateAccount(ctx, accountData)
+
+	accounts, resp, err := c.Accounts.ListAccounts(ctx, &ListAccountsOptions{ListOptions: ListOptions{PageSize: 1}})
+	assert.Nil(t, err, "expecting nil err")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, accounts.Accounts, 1)
+	assert.NotEmpty(t, accounts.Links.First, "expecting a first link")
+}
+
 func TestAccountsService_Delete(t *testing.T) {
 	fixture := readFixture("create-account.json")
 
@@ -0,0 +1,184 @@
+package form3
+
+import (
+	"context"
+)
+
+// AccountIterator iterates over every account, following the JSON:API next
+// link until the last page has been read.
+//
+//	it := c.Accounts.NewAccountIterator(ctx, &form3.ListAccountsOptions{
+//		ListOptions: form3.ListOptions{PageSize: 100},
+//	})
+//	defer it.Close()
+//	for it.Next() {
+//		account := it.Account()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type AccountIterator struct {
+	// Prefetch fetches the next page concurrently while the current page is
+	// being processed. It must be set before the first call to Next.
+	Prefetch bool
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	service *AccountsService
+
+	next    string
+	pending chan pageResult
+
+	page    []*AccountResponseData
+	index   int
+	current *AccountResponseData
+	err     error
+}
+
+type pageResult struct {
+	accounts *AccountListResponse
+	err      error
+}
+
+// NewAccountIterator returns an iterator over all accounts, starting at the
+// page described by opts.
+func (s *AccountsService) NewAccountIterator(ctx context.Context, opts *ListAccountsOptions) *AccountIterator {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &AccountIterator{
+		ctx:     ctx,
+		cancel:  cancel,
+		service: s,
+		next:    addQuery("/v1/organisation/accounts", opts.values()),
+	}
+}
+
+// ForEachAccount calls fn for every account, following the JSON:API next link
+// until the last page has been read. Iteration stops at the first error
+// returned by fn or by the API.
+func (s *AccountsService) ForEachAccount(ctx context.Context, opts *ListAccountsOptions, fn func(*AccountResponseData) error) error {
+	it := s.NewAccountIterator(ctx, opts)
+	defer it.Close()
+
+	for it.Next() {
+		if err := fn(it.Account()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// Next advances the iterator to the next account, fetching the next page when
+// the current one is exhausted. It returns false when there are no accounts
+// left, an error occurred or the iterator was closed.
+func (it *AccountIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.next == "" && it.pending == nil {
+			it.Close()
+			return false
+		}
+
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			it.Close()
+			return false
+		}
+
+		res := it.fetchNext()
+		if res.err != nil {
+			it.err = res.err
+			it.Close()
+			return false
+		}
+
+		it.page = res.accounts.Accounts
+		it.index = 0
+
+		if res.accounts.Links != nil && len(it.page) > 0 {
+			it.next = res.accounts.Links.Next
+		}
+
+		if it.Prefetch && it.next != "" {
+			it.prefetch()
+		}
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+
+	return true
+}
+
+// Account returns the current account.
+func (it *AccountIterator) Account() *AccountResponseData {
+	return it.current
+}
+
+// Err returns the first error that stopped the iteration, if any.
+func (it *AccountIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iteration, cancelling a prefetch in flight and waiting for
+// it to return. It must be called when the iteration is abandoned before Next
+// returns false, and is safe to call more than once.
+func (it *AccountIterator) Close() {
+	it.cancel()
+
+	if it.pending != nil {
+		<-it.pending
+		it.pending = nil
+	}
+
+	it.next = ""
+	it.page = nil
+	it.index = 0
+}
+
+func (it *AccountIterator) fetchNext() pageResult {
+	if it.pending == nil {
+		link := it.next
+		it.next = ""
+
+		return it.fetch(link)
+	}
+
+	select {
+	case res := <-it.pending:
+		it.pending = nil
+		return res
+	case <-it.ctx.Done():
+		return pageResult{err: it.ctx.Err()}
+	}
+}
+
+func (it *AccountIterator) prefetch() {
+	link := it.next
+	it.next = ""
+
+	pending := make(chan pageResult, 1)
+	go func() {
+		pending <- it.fetch(link)
+	}()
+
+	it.pending = pending
+}
+
+func (it *AccountIterator) fetch(link string) pageResult {
+	accounts := new(AccountListResponse)
+
+	ctx := withOperation(it.ctx, &Operation{Name: OperationListAccounts})
+
+	_, err := it.service.client.Follow(ctx, link, accounts)
+	if err != nil {
+		return pageResult{err: err}
+	}
+
+	return pageResult{accounts: accounts}
+}
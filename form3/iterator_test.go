@@ -0,0 +1,205 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// handlePages serves total accounts in pages of page[size], linking every page
+// but the last to the next one.
+func handlePages(t *testing.T, total int) {
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Method, http.MethodGet)
+
+		number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+
+		var data string
+		for i := number * size; i < (number+1)*size && i < total; i++ {
+			if data != "" {
+				data += ","
+			}
+			data += fmt.Sprintf(`{"id":"account-%d","type":"accounts"}`, i)
+		}
+
+		var next string
+		if (number+1)*size < total {
+			next = fmt.Sprintf(`,"next":"/v1/organisation/accounts?page%%5Bnumber%%5D=%d&page%%5Bsize%%5D=%d"`, number+1, size)
+		}
+
+		fmt.Fprintf(w, `{"data":[%v],"links":{"self":"%v"%v}}`, data, r.URL.String(), next)
+	})
+}
+
+func TestAccountIterator(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		t.Run(fmt.Sprintf("prefetch=%v", prefetch), func(t *testing.T) {
+			teardown := setup()
+			defer teardown()
+
+			handlePages(t, 5)
+
+			it := client.Accounts.NewAccountIterator(ctx, &ListAccountsOptions{ListOptions: ListOptions{PageSize: 2}})
+			it.Prefetch = prefetch
+
+			var ids []string
+			for it.Next() {
+				ids = append(ids, it.Account().ID)
+			}
+
+			if err := it.Err(); err != nil {
+				t.Fatalf("AccountIterator returned an error: %v", err)
+			}
+
+			want := []string{"account-0", "account-1", "account-2", "account-3", "account-4"}
+			equal(t, fmt.Sprint(want), fmt.Sprint(ids))
+		})
+	}
+}
+
+func TestAccountIterator_Empty(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	handlePages(t, 0)
+
+	it := client.Accounts.NewAccountIterator(ctx, &ListAccountsOptions{ListOptions: ListOptions{PageSize: 2}})
+
+	if it.Next() {
+		t.Errorf("Expected no accounts, got %+v", it.Account())
+	}
+
+	if err := it.Err(); err != nil {
+		t.Errorf("AccountIterator returned an error: %v", err)
+	}
+}
+
+func TestAccountIterator_HttpError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	})
+
+	it := client.Accounts.NewAccountIterator(ctx, nil)
+
+	if it.Next() {
+		t.Errorf("Expected no accounts, got %+v", it.Account())
+	}
+
+	if err, ok := it.Err().(*ErrorResponse); !ok || err.Status != http.StatusInternalServerError {
+		t.Errorf("Expected an ErrorResponse, got %v", it.Err())
+	}
+}
+
+func TestAccountIterator_ContextCancelled(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	handlePages(t, 5)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	it := client.Accounts.NewAccountIterator(cancelCtx, &ListAccountsOptions{ListOptions: ListOptions{PageSize: 2}})
+	it.Prefetch = true
+
+	count := 0
+	for it.Next() {
+		count++
+		if count == 2 {
+			cancel()
+		}
+	}
+
+	equal(t, count, 2)
+
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", it.Err())
+	}
+}
+
+func TestAccountIterator_Close(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	started := make(chan struct{})
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[number]") == "1" {
+			close(started)
+			<-r.Context().Done()
+			return
+		}
+
+		fmt.Fprint(w, `{"data":[{"id":"account-0","type":"accounts"}],"links":{"next":"/v1/organisation/accounts?page%5Bnumber%5D=1"}}`)
+	})
+
+	it := client.Accounts.NewAccountIterator(ctx, nil)
+	it.Prefetch = true
+
+	if !it.Next() {
+		t.Fatalf("AccountIterator returned no accounts: %v", it.Err())
+	}
+
+	<-started
+
+	// Close returns once the prefetch blocked on the second page has stopped.
+	it.Close()
+	it.Close()
+
+	if it.Next() {
+		t.Errorf("Expected no accounts after Close, got %+v", it.Account())
+	}
+
+	if err := it.Err(); err != nil {
+		t.Errorf("AccountIterator returned an error: %v", err)
+	}
+}
+
+func TestForEachAccount(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	handlePages(t, 3)
+
+	count := 0
+	err := client.Accounts.ForEachAccount(ctx, &ListAccountsOptions{ListOptions: ListOptions{PageSize: 2}}, func(a *AccountResponseData) error {
+		count++
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("ForEachAccount returned an error: %v", err)
+	}
+
+	equal(t, count, 3)
+}
+
+func TestForEachAccount_StopOnError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	handlePages(t, 5)
+
+	stop := errors.New("stop")
+
+	count := 0
+	err := client.Accounts.ForEachAccount(ctx, &ListAccountsOptions{ListOptions: ListOptions{PageSize: 2}}, func(a *AccountResponseData) error {
+		count++
+		if a.ID == "account-2" {
+			return stop
+		}
+		return nil
+	})
+
+	if err != stop {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+
+	equal(t, count, 3)
+}
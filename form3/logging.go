@@ -0,0 +1,191 @@
+package form3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	headerRequestID = "X-Request-Id"
+
+	redacted = "[REDACTED]"
+)
+
+// Logger is the interface used to log API traffic. It is implemented by
+// *slog.Logger; args are alternating keys and values.
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// DefaultRedactPaths are the JSON paths of the account attributes masked in
+// logged bodies by default.
+var DefaultRedactPaths = []string{
+	"data.attributes.account_number",
+	"data.attributes.alternative_names",
+	"data.attributes.iban",
+	"data.attributes.name",
+	"data.attributes.organisation_identification",
+	"data.attributes.private_identification",
+	"data.attributes.secondary_identification",
+}
+
+// RequestLogger logs every request sent to the API with its method, URL,
+// status, latency and request ID. Failed requests are logged as errors.
+type RequestLogger struct {
+	Logger Logger
+
+	// LogBodies adds the request and response bodies to the log, with the
+	// values at RedactPaths masked.
+	LogBodies bool
+
+	// RedactPaths are the dot separated JSON paths masked in logged bodies,
+	// e.g. "data.attributes.iban". Arrays are traversed, so the paths also
+	// match every account of a list response.
+	RedactPaths []string
+}
+
+// NewRequestLogger returns a RequestLogger logging to logger, which redacts
+// DefaultRedactPaths if bodies are logged.
+func NewRequestLogger(logger Logger) *RequestLogger {
+	return &RequestLogger{
+		Logger:      logger,
+		RedactPaths: append([]string(nil), DefaultRedactPaths...),
+	}
+}
+
+// log logs a request sent with send. reqBody is the request body, or nil.
+func (l *RequestLogger) log(req *http.Request, reqBody []byte, resp *http.Response, data []byte, latency time.Duration, err error) {
+	args := []interface{}{
+		"method", req.Method,
+		"url", l.redactURL(req.URL),
+	}
+
+	if resp != nil {
+		args = append(args,
+			"status", resp.StatusCode,
+			"request_id", resp.Header.Get(headerRequestID),
+		)
+	}
+
+	args = append(args, "latency", latency)
+
+	if l.LogBodies {
+		if len(reqBody) > 0 {
+			args = append(args, "request_body", l.redact(reqBody))
+		}
+		if len(data) > 0 {
+			args = append(args, "response_body", l.redact(data))
+		}
+	}
+
+	if err != nil {
+		args = append(args, "error", err)
+		l.Logger.ErrorContext(req.Context(), "form3: request failed", args...)
+		return
+	}
+
+	l.Logger.InfoContext(req.Context(), "form3: request", args...)
+}
+
+// redact returns the JSON body with the values at RedactPaths masked. Bodies
+// that are not JSON are not logged.
+func (l *RequestLogger) redact(body []byte) string {
+	var v interface{}
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return "[non-JSON body]"
+	}
+
+	for _, path := range l.RedactPaths {
+		redactPath(v, strings.Split(path, "."))
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "[non-JSON body]"
+	}
+
+	return string(b)
+}
+
+// redactURL returns the URL with the values of the filter parameters masked
+// whose attribute is at one of RedactPaths, e.g. filter[iban] for
+// "data.attributes.iban".
+func (l *RequestLogger) redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	query := u.Query()
+	masked := false
+
+	for key := range query {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+
+		attribute := attributesPath + key[len("filter["):len(key)-1]
+		for _, path := range l.RedactPaths {
+			if path == attribute {
+				query[key] = []string{redacted}
+				masked = true
+			}
+		}
+	}
+
+	if !masked {
+		return u.String()
+	}
+
+	redactedURL := *u
+	redactedURL.RawQuery = query.Encode()
+
+	return redactedURL.String()
+}
+
+func redactPath(v interface{}, path []string) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			redactPath(item, path)
+		}
+	case map[string]interface{}:
+		value, ok := v[path[0]]
+		if !ok {
+			return
+		}
+
+		if len(path) == 1 {
+			v[path[0]] = redacted
+			return
+		}
+
+		redactPath(value, path[1:])
+	}
+}
+
+// requestBody returns a copy of the body of req, without consuming it.
+func requestBody(req *http.Request) []byte {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	b, _ := io.ReadAll(body)
+
+	return b
+}
@@ -0,0 +1,236 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	args  map[string]interface{}
+}
+
+// fakeLogger records the entries it is asked to log.
+type fakeLogger struct {
+	entries []logEntry
+}
+
+func (l *fakeLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	l.add("INFO", msg, args)
+}
+
+func (l *fakeLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.add("ERROR", msg, args)
+}
+
+func (l *fakeLogger) add(level, msg string, args []interface{}) {
+	entry := logEntry{level: level, msg: msg, args: make(map[string]interface{})}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.args[args[i].(string)] = args[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func TestRequestLogger(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	logger := &fakeLogger{}
+	client.RequestLogger = NewRequestLogger(logger)
+
+	mux.HandleFunc("/v1/organisation/accounts/"+testUUID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		fmt.Fprint(w, readFixture("account-response.json"))
+	})
+
+	_, _, err := client.Accounts.GetAccount(ctx, testUUID)
+	if err != nil {
+		t.Fatalf("GetAccount returned an error: %v", err)
+	}
+
+	if len(logger.entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %v", len(logger.entries))
+	}
+
+	entry := logger.entries[0]
+
+	equal(t, entry.level, "INFO")
+	equal(t, entry.msg, "form3: request")
+	equal(t, entry.args["method"].(string), http.MethodGet)
+	equal(t, entry.args["url"].(string), server.URL+"/v1/organisation/accounts/"+testUUID)
+	equal(t, entry.args["status"].(int), http.StatusOK)
+	equal(t, entry.args["request_id"].(string), "req-1")
+
+	if _, ok := entry.args["latency"].(time.Duration); !ok {
+		t.Errorf("Expected a latency, got %v", entry.args["latency"])
+	}
+
+	if _, ok := entry.args["response_body"]; ok {
+		t.Errorf("Expected no response body")
+	}
+}
+
+func TestRequestLogger_Error(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	logger := &fakeLogger{}
+	client.RequestLogger = NewRequestLogger(logger)
+
+	mux.HandleFunc("/v1/organisation/accounts/"+testUUID, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, readFixture("not-found.json"))
+	})
+
+	_, _, err := client.Accounts.GetAccount(ctx, testUUID)
+
+	entry := logger.entries[0]
+
+	equal(t, entry.level, "ERROR")
+	equal(t, entry.msg, "form3: request failed")
+	equal(t, entry.args["status"].(int), http.StatusNotFound)
+	if entry.args["error"] != err {
+		t.Errorf("Expected the error %v to be logged, got %v", err, entry.args["error"])
+	}
+}
+
+func TestRequestLogger_Bodies(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	logger := &fakeLogger{}
+	client.RequestLogger = NewRequestLogger(logger)
+	client.RequestLogger.LogBodies = true
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, readFixture("account-response.json"))
+	})
+
+	body := createApiResponse[*Account](testdataPath + "create-account.json")
+	body.Data.Attributes.Iban = "GB16NWBK40030041426819"
+
+	_, _, err := client.Accounts.CreateAccount(ctx, body)
+	if err != nil {
+		t.Fatalf("CreateAccount returned an error: %v", err)
+	}
+
+	entry := logger.entries[0]
+
+	for _, key := range []string{"request_body", "response_body"} {
+		logged := entry.args[key].(string)
+
+		if !json.Valid([]byte(logged)) {
+			t.Fatalf("Logged %v is not JSON: %v", key, logged)
+		}
+
+		for _, pii := range []string{"Samantha Holder", "Sam Holder", "GB16NWBK40030041426819", "A1B2C3D4"} {
+			if strings.Contains(logged, pii) {
+				t.Errorf("Logged %v contains %q: %v", key, pii, logged)
+			}
+		}
+
+		if !strings.Contains(logged, `"bic":"NWBKGB22"`) {
+			t.Errorf("Logged %v is missing the BIC: %v", key, logged)
+		}
+	}
+}
+
+func TestRequestLogger_Redact(t *testing.T) {
+	l := &RequestLogger{RedactPaths: []string{"data.attributes.name", "data.id", "links.missing.path"}}
+
+	got := l.redact([]byte(`{"data":[{"id":"1","version":10,"attributes":{"name":["a"],"bic":"b"}},{"id":"2"}],"links":{"self":"/"}}`))
+	want := `{"data":[{"attributes":{"bic":"b","name":"[REDACTED]"},"id":"[REDACTED]","version":10},{"id":"[REDACTED]"}],"links":{"self":"/"}}`
+
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	equal(t, l.redact([]byte("Service unavailable")), "[non-JSON body]")
+}
+
+func TestRequestLogger_RedactFilters(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	logger := &fakeLogger{}
+	client.RequestLogger = NewRequestLogger(logger)
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	opts := &ListAccountsOptions{
+		Iban:          "GB16NWBK40030041426819",
+		AccountNumber: "41426819",
+		Country:       "GB",
+	}
+
+	_, _, err := client.Accounts.ListAccounts(ctx, opts)
+	if err != nil {
+		t.Fatalf("ListAccounts returned an error: %v", err)
+	}
+
+	logged := logger.entries[0].args["url"].(string)
+
+	for _, value := range []string{opts.Iban, opts.AccountNumber} {
+		if strings.Contains(logged, value) {
+			t.Errorf("Logged URL %v contains %v", logged, value)
+		}
+	}
+
+	if !strings.Contains(logged, "filter%5Bcountry%5D=GB") {
+		t.Errorf("Logged URL %v lacks the country filter", logged)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	logger := &fakeLogger{}
+
+	c, err := NewClientWithOptions(WithBaseURL("https://api.form3.tech"), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	if c.RequestLogger.Logger != logger || c.RequestLogger.LogBodies {
+		t.Errorf("WithLogger did not set the logger without bodies")
+	}
+
+	equal(t, len(c.RequestLogger.RedactPaths), len(DefaultRedactPaths))
+
+	if _, err := NewClientWithOptions(WithBaseURL("https://api.form3.tech"), WithLogger(nil)); err == nil {
+		t.Errorf("Expected an error for a nil logger")
+	}
+}
+
+func TestRequestLogger_TransportError(t *testing.T) {
+	logger := &fakeLogger{}
+
+	c, _ := NewClientWithOptions(
+		WithBaseURL("https://api.form3.tech"),
+		WithTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})),
+		WithLogger(logger),
+	)
+
+	req, _ := c.NewRequest(ctx, http.MethodGet, "/v1/health", nil)
+	_, _ = c.SendRequest(req, nil)
+
+	entry := logger.entries[0]
+
+	equal(t, entry.level, "ERROR")
+
+	if _, ok := entry.args["status"]; ok {
+		t.Errorf("Expected no status for a transport error")
+	}
+}
@@ -0,0 +1,26 @@
+package form3
+
+import (
+	"os"
+	"testing"
+
+	"github.com/froedevrolijk/form3-exercise/form3/form3test"
+)
+
+// TestMain runs the integration tests against the in-memory fake account API,
+// unless BASE_URL points them at a running accountapi.
+func TestMain(m *testing.M) {
+	if os.Getenv(defaultBaseUrl) != "" {
+		os.Exit(m.Run())
+	}
+
+	srv := form3test.NewServer()
+
+	os.Setenv(defaultBaseUrl, srv.URL)
+	c = NewClient(nil)
+
+	code := m.Run()
+
+	srv.Close()
+	os.Exit(code)
+}
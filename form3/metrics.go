@@ -0,0 +1,214 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics is the interface used to record metrics of the requests sent with
+// SendRequest. Requests are identified by operation name, e.g.
+// "form3.Accounts.GetAccount", rather than by URL, so that account IDs do not
+// become labels.
+type Metrics interface {
+	// RequestStarted is called before a request is sent.
+	RequestStarted(operation, method string)
+
+	// RequestFinished is called once a request, including its retries, is
+	// done. status is 0 if no response was received.
+	RequestFinished(operation, method string, status int, latency time.Duration, err error)
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency
+// histogram buckets of PrometheusMetrics.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics is a Metrics implementation collecting request counts,
+// error counts by status, latency histograms and in-flight gauges per
+// operation and method. It serves them in the Prometheus text format:
+//
+//	metrics := form3.NewPrometheusMetrics()
+//	http.Handle("/metrics/form3", metrics)
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[metricLabels]float64
+	errors    map[metricLabels]float64
+	inFlight  map[metricLabels]float64
+	latencies map[metricLabels]*histogram
+}
+
+type metricLabels struct {
+	operation string
+	method    string
+	status    string
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics returns a PrometheusMetrics with the given latency
+// buckets, or DefaultLatencyBuckets if none are given.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		buckets:   buckets,
+		requests:  make(map[metricLabels]float64),
+		errors:    make(map[metricLabels]float64),
+		inFlight:  make(map[metricLabels]float64),
+		latencies: make(map[metricLabels]*histogram),
+	}
+}
+
+// RequestStarted increments the in-flight gauge.
+func (m *PrometheusMetrics) RequestStarted(operation, method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight[metricLabels{operation: operation, method: method}]++
+}
+
+// RequestFinished decrements the in-flight gauge and records the request.
+func (m *PrometheusMetrics) RequestFinished(operation, method string, status int, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	labels := metricLabels{operation: operation, method: method}
+
+	m.inFlight[labels]--
+	m.requests[labels]++
+
+	h, ok := m.latencies[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[labels] = h
+	}
+
+	seconds := latency.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+
+	if err != nil {
+		labels.status = "none"
+		if status != 0 {
+			labels.status = strconv.Itoa(status)
+		}
+
+		m.errors[labels]++
+	}
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	writeCounter(&b, "form3_client_requests_total", "counter", "Requests sent to the Form3 API.", m.requests)
+	writeCounter(&b, "form3_client_errors_total", "counter", "Requests to the Form3 API that failed, by status.", m.errors)
+	writeCounter(&b, "form3_client_requests_in_flight", "gauge", "Requests to the Form3 API in flight.", m.inFlight)
+
+	name := "form3_client_request_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %v Latency of requests to the Form3 API, including retries.\n", name)
+	fmt.Fprintf(&b, "# TYPE %v histogram\n", name)
+
+	for _, labels := range sortedLabels(m.latencies) {
+		h := m.latencies[labels]
+
+		for i, bound := range m.buckets {
+			fmt.Fprintf(&b, "%v_bucket{%v,le=%q} %d\n", name, labels, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(&b, "%v_bucket{%v,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(&b, "%v_sum{%v} %v\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "%v_count{%v} %d\n", name, labels, h.count)
+	}
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+func writeCounter(b *strings.Builder, name, typ, help string, values map[metricLabels]float64) {
+	fmt.Fprintf(b, "# HELP %v %v\n", name, help)
+	fmt.Fprintf(b, "# TYPE %v %v\n", name, typ)
+
+	for _, labels := range sortedLabels(values) {
+		fmt.Fprintf(b, "%v{%v} %v\n", name, labels, strconv.FormatFloat(values[labels], 'g', -1, 64))
+	}
+}
+
+func sortedLabels[V any](m map[metricLabels]V) []metricLabels {
+	labels := make([]metricLabels, 0, len(m))
+	for l := range m {
+		labels = append(labels, l)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].String() < labels[j].String()
+	})
+
+	return labels
+}
+
+// String formats the labels in the Prometheus text format.
+func (l metricLabels) String() string {
+	s := fmt.Sprintf("operation=%q,method=%q", l.operation, l.method)
+	if l.status != "" {
+		s += fmt.Sprintf(",status=%q", l.status)
+	}
+
+	return s
+}
+
+// recordMetrics wraps h to record the metrics of every request.
+func (c *Client) recordMetrics(h Handler) Handler {
+	return func(ctx context.Context, req *http.Request) (*Response, error) {
+		operation := operationSendRequest
+		if op, ok := OperationFromContext(ctx); ok {
+			operation = op.Name
+		}
+
+		c.Metrics.RequestStarted(operation, req.Method)
+		start := c.clock.Now()
+
+		resp, err := h(ctx, req)
+
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+
+		c.Metrics.RequestFinished(operation, req.Method, status, c.clock.Now().Sub(start), err)
+
+		return resp, err
+	}
+}
@@ -0,0 +1,143 @@
+package form3
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	clk := setupRetry(nil)
+
+	metrics := NewPrometheusMetrics(0.01, 0.1, 1)
+	client.Metrics = metrics
+
+	var inFlight string
+	mux.HandleFunc("/v1/organisation/accounts/"+testUUID, func(w http.ResponseWriter, r *http.Request) {
+		clk.now = clk.now.Add(50 * time.Millisecond)
+
+		var b strings.Builder
+		_, _ = metrics.WriteTo(&b)
+		inFlight = b.String()
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, readFixture("version-conflict.json"))
+			return
+		}
+		fmt.Fprint(w, readFixture("account-response.json"))
+	})
+
+	_, _, _ = client.Accounts.GetAccount(ctx, testUUID)
+
+	if !strings.Contains(inFlight, `form3_client_requests_in_flight{operation="form3.Accounts.GetAccount",method="GET"} 1`) {
+		t.Errorf("Expected a request in flight, got:\n%v", inFlight)
+	}
+
+	_, _, _ = client.Accounts.GetAccount(ctx, testUUID)
+	_, _ = client.Accounts.DeleteAccount(ctx, &DeleteOptions{AccountID: testUUID, Version: 1})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	want := `# HELP form3_client_requests_total Requests sent to the Form3 API.
+# TYPE form3_client_requests_total counter
+form3_client_requests_total{operation="form3.Accounts.DeleteAccount",method="DELETE"} 1
+form3_client_requests_total{operation="form3.Accounts.GetAccount",method="GET"} 2
+# HELP form3_client_errors_total Requests to the Form3 API that failed, by status.
+# TYPE form3_client_errors_total counter
+form3_client_errors_total{operation="form3.Accounts.DeleteAccount",method="DELETE",status="409"} 1
+# HELP form3_client_requests_in_flight Requests to the Form3 API in flight.
+# TYPE form3_client_requests_in_flight gauge
+form3_client_requests_in_flight{operation="form3.Accounts.DeleteAccount",method="DELETE"} 0
+form3_client_requests_in_flight{operation="form3.Accounts.GetAccount",method="GET"} 0
+# HELP form3_client_request_duration_seconds Latency of requests to the Form3 API, including retries.
+# TYPE form3_client_request_duration_seconds histogram
+form3_client_request_duration_seconds_bucket{operation="form3.Accounts.DeleteAccount",method="DELETE",le="0.01"} 0
+form3_client_request_duration_seconds_bucket{operation="form3.Accounts.DeleteAccount",method="DELETE",le="0.1"} 1
+form3_client_request_duration_seconds_bucket{operation="form3.Accounts.DeleteAccount",method="DELETE",le="1"} 1
+form3_client_request_duration_seconds_bucket{operation="form3.Accounts.DeleteAccount",method="DELETE",le="+Inf"} 1
+form3_client_request_duration_seconds_sum{operation="form3.Accounts.DeleteAccount",method="DELETE"} 0.05
+form3_client_request_duration_seconds_count{operation="form3.Accounts.DeleteAccount",method="DELETE"} 1
+form3_client_request_duration_seconds_bucket{operation="form3.Accounts.GetAccount",method="GET",le="0.01"} 0
+form3_client_request_duration_seconds_bucket{operation="form3.Accounts.GetAccount",method="GET",le="0.1"} 2
+form3_client_request_duration_seconds_bucket{operation="form3.Accounts.GetAccount",method="GET",le="1"} 2
+form3_client_request_duration_seconds_bucket{operation="form3.Accounts.GetAccount",method="GET",le="+Inf"} 2
+form3_client_request_duration_seconds_sum{operation="form3.Accounts.GetAccount",method="GET"} 0.1
+form3_client_request_duration_seconds_count{operation="form3.Accounts.GetAccount",method="GET"} 2
+`
+
+	if got := rec.Body.String(); got != want {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	equal(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+}
+
+func TestPrometheusMetrics_TransportError(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+
+	metrics.RequestStarted(operationSendRequest, http.MethodGet)
+	metrics.RequestFinished(operationSendRequest, http.MethodGet, 0, time.Second, fmt.Errorf("connection refused"))
+
+	var b strings.Builder
+	_, _ = metrics.WriteTo(&b)
+
+	if !strings.Contains(b.String(), `form3_client_errors_total{operation="form3.SendRequest",method="GET",status="none"} 1`) {
+		t.Errorf("Expected a transport error, got:\n%v", b.String())
+	}
+}
+
+type recordingMetrics struct {
+	started  []string
+	finished []string
+}
+
+func (m *recordingMetrics) RequestStarted(operation, method string) {
+	m.started = append(m.started, operation+" "+method)
+}
+
+func (m *recordingMetrics) RequestFinished(operation, method string, status int, latency time.Duration, err error) {
+	m.finished = append(m.finished, fmt.Sprintf("%v %v %d", operation, method, status))
+}
+
+func TestClient_MetricsOncePerCall(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	setupRetry(DefaultRetryPolicy())
+
+	metrics := &recordingMetrics{}
+	client.Metrics = metrics
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+	_, _ = client.SendRequest(req, nil)
+
+	equal(t, calls, 2)
+
+	if !cmp.Equal([]string{"form3.SendRequest GET"}, metrics.started) {
+		t.Errorf("Unexpected started requests: %v", metrics.started)
+	}
+
+	if !cmp.Equal([]string{"form3.SendRequest GET 200"}, metrics.finished) {
+		t.Errorf("Unexpected finished requests: %v", metrics.finished)
+	}
+}
@@ -0,0 +1,31 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+)
+
+// Handler sends a request and decodes its response. It returns the typed
+// error of the request, such as an *ErrorResponse, alongside the response.
+type Handler func(ctx context.Context, req *http.Request) (*Response, error)
+
+// Middleware wraps a Handler, to run code before and after a request is sent.
+// It may return without calling next to short-circuit the request.
+type Middleware func(next Handler) Handler
+
+// Use adds middleware run around every request sent with SendRequest,
+// including retries. Middleware runs in registration order: the first one
+// registered is the outermost. Use must not be called concurrently with
+// requests.
+func (c *Client) Use(mw ...Middleware) {
+	c.middleware = append(c.middleware, mw...)
+}
+
+// handler returns the middleware chain around h.
+func (c *Client) handler(h Handler) Handler {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+
+	return h
+}
@@ -0,0 +1,156 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func recordMiddleware(name string, calls *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*Response, error) {
+			*calls = append(*calls, name+" before")
+			resp, err := next(ctx, req)
+			*calls = append(*calls, name+" after")
+			return resp, err
+		}
+	}
+}
+
+func TestClient_Use(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var calls []string
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "server")
+		fmt.Fprint(w, `{"Field":"v"}`)
+	})
+
+	client.Use(recordMiddleware("a", &calls), recordMiddleware("b", &calls))
+	client.Use(recordMiddleware("c", &calls))
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+	body := new(struct{ Field string })
+
+	_, err := client.SendRequest(req, body)
+	if err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	want := []string{"a before", "b before", "c before", "server", "c after", "b after", "a after"}
+	if !cmp.Equal(want, calls) {
+		t.Error(cmp.Diff(want, calls))
+	}
+
+	equal(t, body.Field, "v")
+}
+
+func TestClient_UseShortCircuit(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request reached the server")
+	})
+
+	errBlocked := errors.New("blocked")
+
+	var calls []string
+	client.Use(
+		recordMiddleware("outer", &calls),
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *http.Request) (*Response, error) {
+				return nil, errBlocked
+			}
+		},
+		recordMiddleware("inner", &calls),
+	)
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	resp, err := client.SendRequest(req, nil)
+
+	if resp != nil || !errors.Is(err, errBlocked) {
+		t.Errorf("Expected the middleware error, got %v, %v", resp, err)
+	}
+
+	want := []string{"outer before", "outer after"}
+	if !cmp.Equal(want, calls) {
+		t.Error(cmp.Diff(want, calls))
+	}
+}
+
+func TestClient_UseSeesResponseAndError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Header.Get("X-Test"), "set by middleware")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, readFixture("not-found.json"))
+	})
+
+	var (
+		status int
+		gotErr error
+	)
+
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*Response, error) {
+			req.Header.Set("X-Test", "set by middleware")
+
+			resp, err := next(ctx, req)
+			status = resp.StatusCode
+			gotErr = err
+
+			return resp, err
+		}
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+	_, _ = client.SendRequest(req, nil)
+
+	equal(t, status, http.StatusNotFound)
+
+	if !errors.Is(gotErr, ErrNotFound) {
+		t.Errorf("Expected the middleware to see ErrNotFound, got %v", gotErr)
+	}
+}
+
+type ctxKey struct{}
+
+func TestClient_UseContext(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	var got interface{}
+
+	client.Use(
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *http.Request) (*Response, error) {
+				return next(context.WithValue(ctx, ctxKey{}, "value"), req)
+			}
+		},
+	)
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		got = r.Context().Value(ctxKey{})
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+	if _, err := client.SendRequest(req, nil); err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	if got != "value" {
+		t.Errorf("Expected the middleware context to be used, got %v", got)
+	}
+}
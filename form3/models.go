@@ -1,6 +1,7 @@
 package form3
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -11,47 +12,194 @@ type Account struct {
 
 // AccountData represents data related to an account.
 type AccountData struct {
-	Attributes     *AccountAttributes `json:"attributes"`
-	ID             string             `json:"id"`
-	OrganisationID string             `json:"organisation_id"`
-	Type           string             `json:"type"`
-	Version        int64              `json:"version"`
+	Attributes     *AccountAttributes    `json:"attributes"`
+	ID             string                `json:"id"`
+	OrganisationID string                `json:"organisation_id"`
+	Relationships  *AccountRelationships `json:"relationships,omitempty"`
+	Type           string                `json:"type"`
+	Version        int64                 `json:"version"`
 }
 
 // AccountAttributes represents account attributes for an account.
 type AccountAttributes struct {
-	AccountClassification   string   `json:"account_classification"`
-	AccountMatchingOptOut   bool     `json:"account_matching_opt_out"`
-	AccountNumber           string   `json:"account_number"`
-	AlternativeNames        []string `json:"alternative_names"`
-	BankID                  string   `json:"bank_id"`
-	BankIDCode              string   `json:"bank_id_code"`
-	BaseCurrency            string   `json:"base_currency"`
-	Bic                     string   `json:"bic"`
-	Country                 string   `json:"country"`
-	Iban                    string   `json:"iban"`
-	JointAccount            bool     `json:"joint_account"`
-	Name                    []string `json:"name"`
-	SecondaryIdentification string   `json:"secondary_identification"`
-	Status                  *string  `json:"status,omitempty"`
-	Switched                bool     `json:"switched"`
+	AcceptanceQualifier        string                      `json:"acceptance_qualifier,omitempty"`
+	AccountClassification      AccountClassification       `json:"account_classification"`
+	AccountMatchingOptOut      bool                        `json:"account_matching_opt_out"`
+	AccountNumber              string                      `json:"account_number"`
+	AccountQualifier           string                      `json:"account_qualifier,omitempty"`
+	AlternativeNames           []string                    `json:"alternative_names"`
+	BankID                     string                      `json:"bank_id"`
+	BankIDCode                 BankIDCode                  `json:"bank_id_code"`
+	BaseCurrency               Currency                    `json:"base_currency"`
+	Bic                        string                      `json:"bic"`
+	Country                    string                      `json:"country"`
+	Iban                       string                      `json:"iban"`
+	JointAccount               bool                        `json:"joint_account"`
+	Name                       []string                    `json:"name"`
+	NameMatchingStatus         string                      `json:"name_matching_status,omitempty"`
+	OrganisationIdentification *OrganisationIdentification `json:"organisation_identification,omitempty"`
+	PrivateIdentification      *PrivateIdentification      `json:"private_identification,omitempty"`
+	ProcessingService          string                      `json:"processing_service,omitempty"`
+	ReferenceMask              string                      `json:"reference_mask,omitempty"`
+	SecondaryIdentification    string                      `json:"secondary_identification"`
+	Status                     *AccountStatus              `json:"status,omitempty"`
+	StatusReason               string                      `json:"status_reason,omitempty"`
+	Switched                   bool                        `json:"switched"`
+	UserDefinedData            []*UserDefinedData          `json:"user_defined_data,omitempty"`
+	ValidationType             string                      `json:"validation_type,omitempty"`
+
+	// Extra holds the attributes not known to this package, keyed by JSON
+	// name. The Client only keeps them in responses if PreserveUnknownFields
+	// is set. They are sent back when the attributes are encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// PrivateIdentification identifies the person holding a personal account.
+type PrivateIdentification struct {
+	Address        []string `json:"address,omitempty"`
+	BirthCountry   string   `json:"birth_country,omitempty"`
+	BirthDate      string   `json:"birth_date,omitempty"`
+	City           string   `json:"city,omitempty"`
+	Country        string   `json:"country,omitempty"`
+	Identification string   `json:"identification,omitempty"`
+}
+
+// OrganisationIdentification identifies the organisation holding a business
+// account.
+type OrganisationIdentification struct {
+	Actors         []*OrganisationActor `json:"actors,omitempty"`
+	Address        []string             `json:"address,omitempty"`
+	City           string               `json:"city,omitempty"`
+	Country        string               `json:"country,omitempty"`
+	Identification string               `json:"identification,omitempty"`
+}
+
+// OrganisationActor is a person acting on behalf of an organisation.
+type OrganisationActor struct {
+	BirthDate string   `json:"birth_date,omitempty"`
+	Name      []string `json:"name,omitempty"`
+	Residency string   `json:"residency,omitempty"`
+}
+
+// UserDefinedData is a key-value pair stored with an account.
+type UserDefinedData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// AccountRelationships represents the resources related to an account.
+type AccountRelationships struct {
+	AccountEvents *Relationship `json:"account_events,omitempty"`
+	MasterAccount *Relationship `json:"master_account,omitempty"`
+}
+
+// Relationship represents a JSON:API relationship to other resources.
+type Relationship struct {
+	Data []*ResourceIdentifier `json:"data"`
+}
+
+// ResourceIdentifier identifies a related resource.
+type ResourceIdentifier struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// AccountPatch represents the request body for updating an account.
+type AccountPatch struct {
+	Data *AccountPatchData `json:"data"`
+}
+
+// AccountPatchData represents the data of an account update. Version must be
+// the current version of the account.
+type AccountPatchData struct {
+	Attributes *AccountAttributesPatch `json:"attributes,omitempty"`
+	ID         string                  `json:"id"`
+	Type       string                  `json:"type"`
+	Version    int64                   `json:"version"`
+}
+
+// AccountAttributesPatch represents the account attributes to change.
+// Nil fields are left unchanged.
+type AccountAttributesPatch struct {
+	AcceptanceQualifier        *string                     `json:"acceptance_qualifier,omitempty"`
+	AccountClassification      *AccountClassification      `json:"account_classification,omitempty"`
+	AccountMatchingOptOut      *bool                       `json:"account_matching_opt_out,omitempty"`
+	AccountNumber              *string                     `json:"account_number,omitempty"`
+	AccountQualifier           *string                     `json:"account_qualifier,omitempty"`
+	AlternativeNames           *[]string                   `json:"alternative_names,omitempty"`
+	BankID                     *string                     `json:"bank_id,omitempty"`
+	BankIDCode                 *BankIDCode                 `json:"bank_id_code,omitempty"`
+	BaseCurrency               *Currency                   `json:"base_currency,omitempty"`
+	Bic                        *string                     `json:"bic,omitempty"`
+	Country                    *string                     `json:"country,omitempty"`
+	Iban                       *string                     `json:"iban,omitempty"`
+	JointAccount               *bool                       `json:"joint_account,omitempty"`
+	Name                       *[]string                   `json:"name,omitempty"`
+	NameMatchingStatus         *string                     `json:"name_matching_status,omitempty"`
+	OrganisationIdentification *OrganisationIdentification `json:"organisation_identification,omitempty"`
+	PrivateIdentification      *PrivateIdentification      `json:"private_identification,omitempty"`
+	ProcessingService          *string                     `json:"processing_service,omitempty"`
+	ReferenceMask              *string                     `json:"reference_mask,omitempty"`
+	SecondaryIdentification    *string                     `json:"secondary_identification,omitempty"`
+	Status                     *AccountStatus              `json:"status,omitempty"`
+	StatusReason               *string                     `json:"status_reason,omitempty"`
+	Switched                   *bool                       `json:"switched,omitempty"`
+	UserDefinedData            *[]*UserDefinedData         `json:"user_defined_data,omitempty"`
+	ValidationType             *string                     `json:"validation_type,omitempty"`
 }
 
 // AccountResponse represents the response for a fetched account.
 type AccountResponse struct {
 	Account *AccountResponseData `json:"data"`
-	Self    string               `json:"self"`
+	Links   *Links               `json:"links"`
 }
 
 // AccountResponseData represents the response data related to a fetched account.
 type AccountResponseData struct {
-	Attributes     *AccountAttributes `json:"attributes"`
-	CreatedOn      time.Time          `json:"created_on"`
-	ID             string             `json:"id"`
-	ModifiedOn     time.Time          `json:"modified_on"`
-	OrganisationID string             `json:"organisation_id"`
-	Type           string             `json:"type"`
-	Version        int                `json:"version"`
+	Attributes     *AccountAttributes    `json:"attributes"`
+	CreatedOn      time.Time             `json:"created_on"`
+	ID             string                `json:"id"`
+	ModifiedOn     time.Time             `json:"modified_on"`
+	OrganisationID string                `json:"organisation_id"`
+	Relationships  *AccountRelationships `json:"relationships,omitempty"`
+	Type           string                `json:"type"`
+	Version        int                   `json:"version"`
+}
+
+// AccountListResponse represents a page of accounts returned by the list endpoint.
+type AccountListResponse struct {
+	Accounts []*AccountResponseData `json:"data"`
+	Links    *Links                 `json:"links"`
+}
+
+// Links represents the JSON:API links of a response. Links are relative to
+// the base URL of the API and can be fetched with Client.Follow.
+type Links struct {
+	First string `json:"first,omitempty"`
+	Last  string `json:"last,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Self  string `json:"self,omitempty"`
+}
+
+// ListOptions represents the pagination URL parameters for list endpoints.
+// Page numbers start at 0; zero values are left to the API defaults.
+type ListOptions struct {
+	PageNumber int
+	PageSize   int
+}
+
+// ListAccountsOptions represents the URL parameters for the list accounts
+// endpoint. Non-empty filter fields are sent as filter[...] parameters.
+type ListAccountsOptions struct {
+	ListOptions
+
+	AccountNumber string
+	BankID        string
+	BankIDCode    BankIDCode
+	Bic           string
+	Country       string
+	Iban          string
 }
 
 // DeleteOptions represents URL parameters for the DELETE endpoint.
@@ -59,3 +207,24 @@ type DeleteOptions struct {
 	AccountID string
 	Version   int64
 }
+
+// String returns a pointer to the string value v.
+func String(v string) *string {
+	return &v
+}
+
+// Bool returns a pointer to the bool value v.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Ptr returns a pointer to the value v, e.g. to set an enum field of an
+// AccountAttributesPatch.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Strings returns a pointer to a slice holding the string values v.
+func Strings(v ...string) *[]string {
+	return &v
+}
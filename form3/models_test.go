@@ -0,0 +1,67 @@
+package form3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAccountResponse_Golden(t *testing.T) {
+	golden := readFixture("account-full.json")
+
+	account := new(AccountResponse)
+	if err := json.Unmarshal([]byte(golden), account); err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+
+	b, err := json.MarshalIndent(account, "", "    ")
+	if err != nil {
+		t.Fatalf("MarshalIndent returned an error: %v", err)
+	}
+
+	if string(b) != golden {
+		t.Error(cmp.Diff(golden, string(b)))
+	}
+}
+
+func TestAccountResponse_GoldenFields(t *testing.T) {
+	account := createApiResponse[*AccountResponse](testdataPath + "account-full.json")
+	attrs := account.Account.Attributes
+
+	equal(t, attrs.PrivateIdentification.BirthDate, "2017-07-23")
+	equal(t, attrs.PrivateIdentification.Address[0], "10 Avenue des Champs")
+	equal(t, attrs.OrganisationIdentification.Actors[0].Residency, "GB")
+	equal(t, attrs.UserDefinedData[0].Value, "Some account related value")
+	equal(t, attrs.NameMatchingStatus, "supported")
+	equal(t, *attrs.Status, AccountStatusConfirmed)
+	equal(t, account.Account.Relationships.MasterAccount.Data[0].ID, "a52d13a4-f435-4c00-cfad-f5e7ac5972df")
+	equal(t, account.Account.Relationships.AccountEvents.Data[0].Type, "account_events")
+}
+
+func TestAccount_GoldenRoundTrip(t *testing.T) {
+	response := createApiResponse[*AccountResponse](testdataPath + "account-full.json")
+
+	account := &Account{Data: &AccountData{
+		Attributes:     response.Account.Attributes,
+		ID:             response.Account.ID,
+		OrganisationID: response.Account.OrganisationID,
+		Relationships:  response.Account.Relationships,
+		Type:           response.Account.Type,
+		Version:        int64(response.Account.Version),
+	}}
+
+	b, err := json.Marshal(account)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+
+	got := new(Account)
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+
+	if !cmp.Equal(account, got) {
+		t.Error(cmp.Diff(account, got))
+	}
+}
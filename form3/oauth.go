@@ -0,0 +1,314 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const defaultExpiryDelta = 30 * time.Second
+
+// Token represents an OAuth2 access token.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+
+	// Expiry is the time the token expires. The zero value means the token
+	// does not expire.
+	Expiry time.Time `json:"-"`
+}
+
+// TokenSource returns OAuth2 access tokens for the Authorization header.
+type TokenSource interface {
+	// Token returns a valid token.
+	Token(ctx context.Context) (*Token, error)
+
+	// Invalidate discards a token rejected by the API, so that the next call
+	// to Token returns a new one.
+	Invalidate(token *Token)
+}
+
+// ClientCredentials is a TokenSource that fetches tokens with the OAuth2
+// client credentials grant. Tokens are cached until shortly before they
+// expire, and concurrent callers share a single token request.
+//
+// Form3 API docs: https://www.api-docs.form3.tech/api/schemes/bacs/introduction/security/oauth2-authentication
+type ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+
+	// TokenURL is the token endpoint, e.g. "https://api.form3.tech/v1/oauth2/token".
+	TokenURL string
+
+	// HTTPClient is used to fetch tokens. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// ExpiryDelta is how long before its expiry a token is refreshed.
+	// If zero, tokens are refreshed 30 seconds before they expire.
+	ExpiryDelta time.Duration
+
+	mu    sync.Mutex
+	token *Token
+	group singleflight.Group
+	now   func() time.Time
+}
+
+// NewClientCredentials returns a ClientCredentials token source.
+func NewClientCredentials(clientID, clientSecret, tokenURL string) *ClientCredentials {
+	return &ClientCredentials{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+	}
+}
+
+// Token returns the cached token, or fetches a new one if it is missing or
+// about to expire.
+func (s *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	if token := s.cached(); token != nil {
+		return token, nil
+	}
+
+	ch := s.group.DoChan("token", func() (interface{}, error) {
+		if token := s.cached(); token != nil {
+			return token, nil
+		}
+
+		// The fetch is shared with the callers waiting for it, so it must not
+		// be cancelled with the context of the caller that started it.
+		fetchCtx, cancel := context.WithTimeout(detachedContext{ctx}, s.fetchTimeout())
+		defer cancel()
+
+		token, err := s.fetch(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		s.token = token
+		s.mu.Unlock()
+
+		return token, nil
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*Token), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Invalidate discards the cached token if it is the given one.
+func (s *ClientCredentials) Invalidate(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = nil
+	}
+}
+
+func (s *ClientCredentials) cached() *Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		return nil
+	}
+
+	if s.token.Expiry.IsZero() || s.timeNow().Add(s.expiryDelta()).Before(s.token.Expiry) {
+		return s.token
+	}
+
+	return nil
+}
+
+func (s *ClientCredentials) fetch(ctx context.Context) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(url.QueryEscape(s.ClientID), url.QueryEscape(s.ClientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := CheckResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	token := new(Token)
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken == "" {
+		return nil, errors.New("form3: token response has no access token")
+	}
+
+	if token.ExpiresIn > 0 {
+		token.Expiry = s.timeNow().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+func (s *ClientCredentials) httpClient() *http.Client {
+	if s.HTTPClient == nil {
+		return http.DefaultClient
+	}
+
+	return s.HTTPClient
+}
+
+// fetchTimeout is the timeout of a token request: the timeout of the HTTP
+// client, or 10 seconds if it has none.
+func (s *ClientCredentials) fetchTimeout() time.Duration {
+	if timeout := s.httpClient().Timeout; timeout > 0 {
+		return timeout
+	}
+
+	return defaultTimeout
+}
+
+func (s *ClientCredentials) expiryDelta() time.Duration {
+	if s.ExpiryDelta == 0 {
+		return defaultExpiryDelta
+	}
+
+	return s.ExpiryDelta
+}
+
+func (s *ClientCredentials) timeNow() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+
+	return s.now()
+}
+
+// detachedContext carries the values of its parent context, but not its
+// deadline or cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// BearerTransport is an http.RoundTripper that adds an
+// "Authorization: Bearer" header to every request. If the API responds with
+// 401 Unauthorized, the token is invalidated and the request is retried once
+// with a new token.
+type BearerTransport struct {
+	Source TokenSource
+
+	// Base is the RoundTripper used to send the requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// WithTokenSource authenticates every request sent by the client with a
+// bearer token from source, wrapping the client's transport in a
+// BearerTransport. The transport is wrapped even if it is set by a later
+// option.
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Client) error {
+		if source == nil {
+			return errors.New("form3: token source is nil")
+		}
+
+		c.wrapTransport(func(base http.RoundTripper) http.RoundTripper {
+			return &BearerTransport{Source: source, Base: base}
+		})
+
+		return nil
+	}
+}
+
+// RoundTrip authenticates a copy of the request and sends it with the base
+// transport.
+func (t *BearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Source.Token(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	resp, err := t.base().RoundTrip(authorize(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	t.Source.Invalidate(token)
+
+	token, err = t.Source.Token(req.Context())
+	if err != nil {
+		return resp, nil
+	}
+
+	retry := authorize(req, token)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return t.base().RoundTrip(retry)
+}
+
+func authorize(req *http.Request, token *Token) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	return r
+}
+
+func (t *BearerTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+
+	return t.Base
+}
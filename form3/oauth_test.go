@@ -0,0 +1,282 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// handleTokens serves tokens from the token endpoint, numbering them in the
+// order they were issued.
+func handleTokens(t *testing.T, expiresIn int) *int32 {
+	var issued int32
+
+	mux.HandleFunc("/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Method, http.MethodPost)
+
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client-id" || secret != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error_message":"invalid client credentials"}`)
+			return
+		}
+
+		_ = r.ParseForm()
+		equal(t, r.PostForm.Get("grant_type"), "client_credentials")
+
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, n, expiresIn)
+	})
+
+	return &issued
+}
+
+func newTestClientCredentials() *ClientCredentials {
+	return NewClientCredentials("client-id", "client-secret", server.URL+"/v1/oauth2/token")
+}
+
+func TestClientCredentials_Token(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	issued := handleTokens(t, 3600)
+
+	source := newTestClientCredentials()
+
+	for i := 0; i < 3; i++ {
+		token, err := source.Token(ctx)
+		if err != nil {
+			t.Fatalf("Token returned an error: %v", err)
+		}
+
+		equal(t, token.AccessToken, "token-1")
+	}
+
+	equal(t, atomic.LoadInt32(issued), int32(1))
+}
+
+func TestClientCredentials_Concurrent(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	issued := handleTokens(t, 3600)
+
+	source := newTestClientCredentials()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := source.Token(ctx); err != nil {
+				t.Errorf("Token returned an error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	equal(t, atomic.LoadInt32(issued), int32(1))
+}
+
+func TestClientCredentials_Refresh(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	issued := handleTokens(t, 60)
+
+	now := time.Date(2022, 10, 23, 15, 50, 0, 0, time.UTC)
+
+	source := newTestClientCredentials()
+	source.now = func() time.Time { return now }
+
+	token, _ := source.Token(ctx)
+	equal(t, token.AccessToken, "token-1")
+	equal(t, token.Expiry, now.Add(time.Minute))
+
+	now = now.Add(20 * time.Second)
+	token, _ = source.Token(ctx)
+	equal(t, token.AccessToken, "token-1")
+
+	now = now.Add(20 * time.Second)
+	token, _ = source.Token(ctx)
+	equal(t, token.AccessToken, "token-2")
+
+	equal(t, atomic.LoadInt32(issued), int32(2))
+}
+
+func TestClientCredentials_Error(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	handleTokens(t, 3600)
+
+	source := NewClientCredentials("client-id", "wrong-secret", server.URL+"/v1/oauth2/token")
+
+	_, err := source.Token(ctx)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected an unauthorized error, got %v", err)
+	}
+}
+
+func TestClientCredentials_ContextCancelled(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	handleTokens(t, 3600)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err := newTestClientCredentials().Token(cancelCtx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClientCredentials_FirstCallerCancelled(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	mux.HandleFunc("/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		fmt.Fprint(w, `{"access_token":"token-1","token_type":"bearer","expires_in":3600}`)
+	})
+
+	source := newTestClientCredentials()
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	first := make(chan error, 1)
+	go func() {
+		_, err := source.Token(cancelCtx)
+		first <- err
+	}()
+
+	<-entered
+
+	second := make(chan error, 1)
+	go func() {
+		token, err := source.Token(ctx)
+		if err == nil && token.AccessToken != "token-1" {
+			err = fmt.Errorf("got token %q", token.AccessToken)
+		}
+		second <- err
+	}()
+
+	// Give the second caller time to join the token request in flight.
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled for the first caller, got %v", err)
+	}
+
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("Token returned an error for the second caller: %v", err)
+	}
+}
+
+func TestBearerTransport(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	handleTokens(t, 3600)
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		equal(t, r.Header.Get("Authorization"), "Bearer token-1")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client.client = &http.Client{Transport: &BearerTransport{Source: newTestClientCredentials()}}
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/v1/organisation/accounts", nil)
+
+	_, err := client.SendRequest(req, nil)
+	if err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+}
+
+func TestBearerTransport_RetryOnUnauthorized(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	issued := handleTokens(t, 3600)
+
+	var bodies []string
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	client.client = &http.Client{Transport: &BearerTransport{Source: newTestClientCredentials()}}
+
+	req, _ := client.NewRequest(ctx, http.MethodPost, "/v1/organisation/accounts", map[string]string{"id": testUUID})
+
+	resp, err := client.SendRequest(req, nil)
+	if err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	want := fmt.Sprintf("{\"id\":\"%v\"}\n", testUUID)
+
+	equal(t, resp.StatusCode, http.StatusCreated)
+	equal(t, atomic.LoadInt32(issued), int32(2))
+	equal(t, fmt.Sprint(bodies), fmt.Sprint([]string{want, want}))
+}
+
+func TestBearerTransport_RetryOnlyOnce(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	issued := handleTokens(t, 3600)
+
+	calls := 0
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	client.client = &http.Client{Transport: &BearerTransport{Source: newTestClientCredentials()}}
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/v1/organisation/accounts", nil)
+
+	_, err := client.SendRequest(req, nil)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Expected an unauthorized error, got %v", err)
+	}
+
+	equal(t, calls, 2)
+	equal(t, atomic.LoadInt32(issued), int32(2))
+}
+
+func TestWithTokenSource(t *testing.T) {
+	c, err := NewClientWithOptions(WithTokenSource(NewClientCredentials("client-id", "client-secret", "https://api.form3.tech/v1/oauth2/token")))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	if _, ok := c.Client().Transport.(*BearerTransport); !ok {
+		t.Errorf("WithTokenSource Transport; got %T", c.Client().Transport)
+	}
+
+	if _, err := NewClientWithOptions(WithTokenSource(nil)); err == nil {
+		t.Errorf("Expected an error for a nil token source")
+	}
+}
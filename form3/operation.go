@@ -0,0 +1,59 @@
+package form3
+
+import "context"
+
+// Names of the operations of the service methods.
+const (
+	OperationGetAccount    = "form3.Accounts.GetAccount"
+	OperationListAccounts  = "form3.Accounts.ListAccounts"
+	OperationCreateAccount = "form3.Accounts.CreateAccount"
+	OperationUpdateAccount = "form3.Accounts.UpdateAccount"
+	OperationDeleteAccount = "form3.Accounts.DeleteAccount"
+
+	// operationSendRequest names requests not sent by a service method.
+	operationSendRequest = "form3.SendRequest"
+)
+
+// Operation describes the service method a request is sent for. It is
+// available to middleware through OperationFromContext, e.g. to name spans
+// and metrics without using the request URL.
+type Operation struct {
+	// Name is the name of the service method, e.g.
+	// "form3.Accounts.CreateAccount".
+	Name string
+
+	// AccountID and OrganisationID identify the account the request is for,
+	// if known. They are filled in from the response once it is decoded.
+	AccountID      string
+	OrganisationID string
+}
+
+type operationKey struct{}
+
+// OperationFromContext returns the operation of the request with context ctx.
+// It returns false for requests not sent by a service method.
+func OperationFromContext(ctx context.Context) (*Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(*Operation)
+	return op, ok
+}
+
+func withOperation(ctx context.Context, op *Operation) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// identify fills in the account and organisation IDs of the operation from
+// the decoded response v, if they are not known yet.
+func (op *Operation) identify(v interface{}) {
+	r, ok := v.(*AccountResponse)
+	if !ok || r.Account == nil {
+		return
+	}
+
+	if op.AccountID == "" {
+		op.AccountID = r.Account.ID
+	}
+
+	if op.OrganisationID == "" {
+		op.OrganisationID = r.Account.OrganisationID
+	}
+}
@@ -0,0 +1,66 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOperationFromContext(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, readFixture("account-response.json"))
+			return
+		}
+		fmt.Fprint(w, readFixture("account-list-response.json"))
+	})
+	mux.HandleFunc("/v1/organisation/accounts/"+testUUID, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		fmt.Fprint(w, readFixture("account-response.json"))
+	})
+
+	var got []Operation
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			if op, ok := OperationFromContext(ctx); ok {
+				got = append(got, *op)
+			}
+			return resp, err
+		}
+	})
+
+	orgID := "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+
+	_, _, _ = client.Accounts.GetAccount(ctx, testUUID)
+	_, _, _ = client.Accounts.ListAccounts(ctx, nil)
+	_, _, _ = client.Accounts.CreateAccount(ctx, createApiResponse[*Account](testdataPath+"create-account.json"))
+	_, _, _ = client.Accounts.UpdateAccount(ctx, createApiResponse[*AccountPatch](testdataPath+"update-account.json"))
+	_, _ = client.Accounts.DeleteAccount(ctx, &DeleteOptions{AccountID: testUUID})
+
+	want := []Operation{
+		{Name: OperationGetAccount, AccountID: testUUID, OrganisationID: orgID},
+		{Name: OperationListAccounts},
+		{Name: OperationCreateAccount, AccountID: testUUID, OrganisationID: orgID},
+		{Name: OperationUpdateAccount, AccountID: testUUID, OrganisationID: orgID},
+		{Name: OperationDeleteAccount, AccountID: testUUID},
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	if _, ok := OperationFromContext(ctx); ok {
+		t.Errorf("Expected no operation in a plain context")
+	}
+}
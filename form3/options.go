@@ -0,0 +1,248 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client created with NewClientWithOptions.
+type Option func(*Client) error
+
+// NewClientWithOptions returns a new Form3 API client configured with opts.
+// Unless WithBaseURL is given, the base URL is read from the BASE_URL
+// environment variable. The client uses its own http.Client with a 10 second
+// timeout unless WithHTTPClient is given; shared clients are never modified.
+// Options changing the timeout or transport apply to the client given with
+// WithHTTPClient, wherever it appears in opts. WithSigner and WithTokenSource
+// always wrap the resulting transport.
+func NewClientWithOptions(opts ...Option) (*Client, error) {
+	c := newClient(&http.Client{Timeout: defaultTimeout}, nil)
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, configure := range c.httpClientOptions {
+		if err := configure(c.client); err != nil {
+			return nil, err
+		}
+	}
+	c.httpClientOptions = nil
+
+	for _, wrap := range c.transportWrappers {
+		c.client.Transport = wrap(c.client.Transport)
+	}
+	c.transportWrappers = nil
+
+	if c.BaseUrl == nil {
+		baseUrl, err := parseBaseUrl(getBaseUrl())
+		if err != nil {
+			return nil, err
+		}
+
+		c.BaseUrl = baseUrl
+	}
+
+	return c, nil
+}
+
+// WithBaseURL sets the base URL of the API, e.g. "https://api.form3.tech".
+func WithBaseURL(rawUrl string) Option {
+	return func(c *Client) error {
+		baseUrl, err := parseBaseUrl(rawUrl)
+		if err != nil {
+			return err
+		}
+
+		c.BaseUrl = baseUrl
+
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests. The client is
+// copied, so options do not modify it. Options changing the timeout or
+// transport apply to the copy, whether they come before or after it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("form3: HTTP client is nil")
+		}
+
+		hc := *httpClient
+		c.client = &hc
+
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of every request, including retries.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("form3: invalid timeout %v", timeout)
+		}
+
+		c.configureHTTPClient(func(hc *http.Client) error {
+			hc.Timeout = timeout
+			return nil
+		})
+
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.userAgent = userAgent
+
+		return nil
+	}
+}
+
+// WithTransport sets the RoundTripper used to send requests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) error {
+		if transport == nil {
+			return errors.New("form3: transport is nil")
+		}
+
+		c.configureHTTPClient(func(hc *http.Client) error {
+			hc.Transport = transport
+			return nil
+		})
+
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) error {
+		c.RetryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithRateLimiter sets the limiter used to throttle outgoing requests.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) error {
+		c.RateLimiter = limiter
+
+		return nil
+	}
+}
+
+// WithValidation validates accounts before they are sent to the API.
+func WithValidation() Option {
+	return func(c *Client) error {
+		c.ValidateRequests = true
+
+		return nil
+	}
+}
+
+// WithLenientEnums keeps unknown enum values in decoded responses instead of
+// rejecting them.
+func WithLenientEnums() Option {
+	return func(c *Client) error {
+		c.LenientEnums = true
+
+		return nil
+	}
+}
+
+// WithPreserveUnknownFields keeps unknown account attributes of decoded
+// responses in AccountAttributes.Extra, so that they are sent back when an
+// account is updated.
+func WithPreserveUnknownFields() Option {
+	return func(c *Client) error {
+		c.PreserveUnknownFields = true
+
+		return nil
+	}
+}
+
+// WithLogger logs every request to logger, without bodies. Use
+// WithRequestLogger to also log redacted bodies.
+func WithLogger(logger Logger) Option {
+	return WithRequestLogger(NewRequestLogger(logger))
+}
+
+// WithRequestLogger sets the logger used to log requests.
+func WithRequestLogger(logger *RequestLogger) Option {
+	return func(c *Client) error {
+		if logger == nil || logger.Logger == nil {
+			return errors.New("form3: logger is nil")
+		}
+
+		c.RequestLogger = logger
+
+		return nil
+	}
+}
+
+// WithMiddleware adds middleware run around every request, see Client.Use.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) error {
+		c.Use(mw...)
+
+		return nil
+	}
+}
+
+// WithMetrics sets the hook used to record metrics of every request.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) error {
+		c.Metrics = metrics
+
+		return nil
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker used to fail requests fast
+// while the API is failing.
+func WithCircuitBreaker(cb *CircuitBreaker) Option {
+	return func(c *Client) error {
+		c.CircuitBreaker = cb
+
+		return nil
+	}
+}
+
+// configureHTTPClient queues a change of the HTTP client, applied in order
+// once all options ran.
+func (c *Client) configureHTTPClient(configure func(*http.Client) error) {
+	c.httpClientOptions = append(c.httpClientOptions, configure)
+}
+
+// wrapTransport queues a wrapper of the transport, applied in order once the
+// HTTP client changes were applied, so that options setting the transport do
+// not replace it.
+func (c *Client) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	c.transportWrappers = append(c.transportWrappers, wrap)
+}
+
+func parseBaseUrl(rawUrl string) (*url.URL, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, fmt.Errorf("form3: invalid base URL %q: %w", rawUrl, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("form3: invalid base URL %q: scheme must be http or https", rawUrl)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("form3: invalid base URL %q: missing host", rawUrl)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,211 @@
+package form3
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	timeout := http.DefaultClient.Timeout
+
+	c, err := NewClientWithOptions()
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	equal(t, c.BaseUrl.String(), getBaseUrl())
+	equal(t, c.Client().Timeout, defaultTimeout)
+
+	if c.Client() == http.DefaultClient {
+		t.Errorf("NewClientWithOptions uses http.DefaultClient")
+	}
+
+	equal(t, http.DefaultClient.Timeout, timeout)
+}
+
+func TestNewClientWithOptions_Options(t *testing.T) {
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+
+	policy := DefaultRetryPolicy()
+	limiter, _ := NewRateLimiter(1, 1)
+
+	c, err := NewClientWithOptions(
+		WithBaseURL("https://api.form3.tech"),
+		WithTimeout(30*time.Second),
+		WithUserAgent("form3-exercise/1.0"),
+		WithTransport(transport),
+		WithRetryPolicy(policy),
+		WithRateLimiter(limiter),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	equal(t, c.BaseUrl.String(), "https://api.form3.tech")
+	equal(t, c.Client().Timeout, 30*time.Second)
+	if c.RetryPolicy != policy {
+		t.Errorf("NewClientWithOptions RetryPolicy; got %+v, want %+v", c.RetryPolicy, policy)
+	}
+
+	if c.RateLimiter != limiter {
+		t.Errorf("NewClientWithOptions RateLimiter; got %p, want %p", c.RateLimiter, limiter)
+	}
+
+	if _, ok := c.Client().Transport.(roundTripperFunc); !ok {
+		t.Errorf("NewClientWithOptions Transport; got %T", c.Client().Transport)
+	}
+
+	req, _ := c.NewRequest(ctx, http.MethodGet, "/v1/organisation/accounts", nil)
+	equal(t, req.URL.String(), "https://api.form3.tech/v1/organisation/accounts")
+	equal(t, req.Header.Get("User-Agent"), "form3-exercise/1.0")
+}
+
+func TestNewClientWithOptions_HTTPClientNotModified(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Second}
+
+	c, err := NewClientWithOptions(WithHTTPClient(httpClient), WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	equal(t, c.Client().Timeout, time.Minute)
+	equal(t, httpClient.Timeout, time.Second)
+}
+
+func TestNewClientWithOptions_HTTPClientLast(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Second}
+
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+
+	c, err := NewClientWithOptions(
+		WithTimeout(time.Minute),
+		WithTransport(transport),
+		WithTokenSource(NewClientCredentials("client-id", "client-secret", "https://api.form3.tech/v1/oauth2/token")),
+		WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	equal(t, c.Client().Timeout, time.Minute)
+	equal(t, httpClient.Timeout, time.Second)
+
+	bearer, ok := c.Client().Transport.(*BearerTransport)
+	if !ok {
+		t.Fatalf("Expected a BearerTransport, got %T", c.Client().Transport)
+	}
+
+	if _, ok := bearer.Base.(roundTripperFunc); !ok {
+		t.Errorf("Expected the BearerTransport to wrap the transport, got %T", bearer.Base)
+	}
+}
+
+func TestNewClientWithOptions_WrapTransportLast(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+
+	signer := WithSigner("a-key-id", key)
+	bearer := WithTokenSource(NewClientCredentials("client-id", "client-secret", "https://api.form3.tech/v1/oauth2/token"))
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"signer then transport", []Option{signer, bearer, WithTransport(transport)}},
+		{"transport then signer", []Option{WithTransport(transport), signer, bearer}},
+		{"signer then HTTP client", []Option{signer, bearer, WithHTTPClient(&http.Client{Transport: transport})}},
+		{"HTTP client then signer", []Option{WithHTTPClient(&http.Client{Transport: transport}), signer, bearer}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClientWithOptions(tt.opts...)
+			if err != nil {
+				t.Fatalf("NewClientWithOptions returned an error: %v", err)
+			}
+
+			bt, ok := c.Client().Transport.(*BearerTransport)
+			if !ok {
+				t.Fatalf("Expected a BearerTransport, got %T", c.Client().Transport)
+			}
+
+			st, ok := bt.Base.(*SigningTransport)
+			if !ok {
+				t.Fatalf("Expected the BearerTransport to wrap a SigningTransport, got %T", bt.Base)
+			}
+
+			if _, ok := st.Base.(roundTripperFunc); !ok {
+				t.Errorf("Expected the SigningTransport to wrap the transport, got %T", st.Base)
+			}
+		})
+	}
+}
+
+func TestNewClientWithOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		want string
+	}{
+		{
+			name: "unparsable base url",
+			opt:  WithBaseURL("http://a b.com/"),
+			want: "invalid base URL",
+		},
+		{
+			name: "missing scheme",
+			opt:  WithBaseURL("api.form3.tech"),
+			want: "scheme must be http or https",
+		},
+		{
+			name: "missing host",
+			opt:  WithBaseURL("https://"),
+			want: "missing host",
+		},
+		{
+			name: "nil http client",
+			opt:  WithHTTPClient(nil),
+			want: "HTTP client is nil",
+		},
+		{
+			name: "nil transport",
+			opt:  WithTransport(nil),
+			want: "transport is nil",
+		},
+		{
+			name: "negative timeout",
+			opt:  WithTimeout(-time.Second),
+			want: "invalid timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClientWithOptions(tt.opt)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewClientWithOptions error; got %v, want %v", err, tt.want)
+			}
+
+			if c != nil {
+				t.Errorf("NewClientWithOptions returned a client on error")
+			}
+		})
+	}
+}
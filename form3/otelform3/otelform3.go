@@ -0,0 +1,136 @@
+// Package otelform3 instruments a form3.Client with OpenTelemetry tracing.
+//
+//	c, _ := form3.NewClientWithOptions(form3.WithMiddleware(otelform3.Middleware()))
+//
+// Every request sent by a service method is traced with a client span named
+// after the method, e.g. "form3.Accounts.CreateAccount", and the W3C trace
+// context of the span is sent to the API.
+package otelform3
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/froedevrolijk/form3-exercise/form3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/froedevrolijk/form3-exercise/form3/otelform3"
+
+	// defaultSpanName names the spans of requests not sent by a service
+	// method, e.g. with Client.Follow.
+	defaultSpanName = "form3.SendRequest"
+)
+
+// Attribute keys set on spans, in addition to the HTTP semantic conventions.
+const (
+	AccountIDKey      = attribute.Key("form3.account_id")
+	OrganisationIDKey = attribute.Key("form3.organisation_id")
+	RetryCountKey     = attribute.Key("form3.retry_count")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option configures the middleware.
+type Option func(*config)
+
+// WithTracerProvider sets the provider of the tracer used to start spans.
+// The global provider is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithPropagator sets the propagator used to send the trace context to the
+// API. W3C trace context is used by default.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = p
+	}
+}
+
+// Middleware returns a form3.Middleware tracing every request.
+func Middleware(opts ...Option) form3.Middleware {
+	cfg := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     propagation.TraceContext{},
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+
+	return func(next form3.Handler) form3.Handler {
+		return func(ctx context.Context, req *http.Request) (*form3.Response, error) {
+			name := defaultSpanName
+			op, ok := form3.OperationFromContext(ctx)
+			if ok {
+				name = op.Name
+			}
+
+			ctx, span := tracer.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(req.Method),
+					semconv.HTTPURLKey.String(spanURL(req.URL)),
+				),
+			)
+			defer span.End()
+
+			cfg.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(ctx, req)
+
+			if ok {
+				setAttribute(span, AccountIDKey, op.AccountID)
+				setAttribute(span, OrganisationIDKey, op.OrganisationID)
+			}
+
+			if resp != nil {
+				span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+
+				// Responses made up by a later middleware have no attempts.
+				if resp.Attempts > 0 {
+					span.SetAttributes(RetryCountKey.Int(resp.Attempts - 1))
+				}
+			}
+
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// spanURL returns the URL without its query and credentials, which may hold
+// personal data such as the IBAN of a list filter.
+func spanURL(u *url.URL) string {
+	stripped := *u
+	stripped.User = nil
+	stripped.RawQuery = ""
+	stripped.ForceQuery = false
+
+	return stripped.String()
+}
+
+func setAttribute(span trace.Span, key attribute.Key, value string) {
+	if value != "" {
+		span.SetAttributes(key.String(value))
+	}
+}
@@ -0,0 +1,203 @@
+package otelform3_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/froedevrolijk/form3-exercise/form3"
+	"github.com/froedevrolijk/form3-exercise/form3/form3test"
+	"github.com/froedevrolijk/form3-exercise/form3/otelform3"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var ctx = context.Background()
+
+const organisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+
+func setup(t *testing.T, opts ...form3.Option) (*form3.Client, *tracetest.InMemoryExporter) {
+	srv := form3test.NewServer()
+	t.Cleanup(srv.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	opts = append([]form3.Option{
+		form3.WithBaseURL(srv.URL),
+		form3.WithMiddleware(otelform3.Middleware(otelform3.WithTracerProvider(tp))),
+	}, opts...)
+
+	c, err := form3.NewClientWithOptions(opts...)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	return c, exporter
+}
+
+func newAccount(id string) *form3.Account {
+	return &form3.Account{
+		Data: &form3.AccountData{
+			Type:           "accounts",
+			ID:             id,
+			OrganisationID: organisationID,
+			Attributes: &form3.AccountAttributes{
+				Country: "GB",
+				Name:    []string{"Samantha Holder"},
+			},
+		},
+	}
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestMiddleware(t *testing.T) {
+	c, exporter := setup(t)
+
+	id := uuid.NewString()
+
+	_, _, err := c.Accounts.CreateAccount(ctx, newAccount(id))
+	assert.Nil(t, err, "expecting nil err")
+
+	_, _, err = c.Accounts.GetAccount(ctx, id)
+	assert.Nil(t, err, "expecting nil err")
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, form3.OperationCreateAccount, spans[0].Name)
+	assert.Equal(t, form3.OperationGetAccount, spans[1].Name)
+
+	for _, span := range spans {
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+		assert.Equal(t, codes.Unset, span.Status.Code)
+
+		attrs := attributes(span)
+		assert.Equal(t, id, attrs[otelform3.AccountIDKey].AsString())
+		assert.Equal(t, organisationID, attrs[otelform3.OrganisationIDKey].AsString())
+		assert.Equal(t, int64(0), attrs[otelform3.RetryCountKey].AsInt64())
+	}
+
+	assert.Equal(t, int64(http.StatusCreated), attributes(spans[0])["http.status_code"].AsInt64())
+	assert.Equal(t, "POST", attributes(spans[0])["http.method"].AsString())
+	assert.Equal(t, int64(http.StatusOK), attributes(spans[1])["http.status_code"].AsInt64())
+}
+
+func TestMiddleware_URLWithoutQuery(t *testing.T) {
+	c, exporter := setup(t)
+
+	_, _, err := c.Accounts.ListAccounts(ctx, &form3.ListAccountsOptions{Iban: "GB16NWBK40030041426819"})
+	assert.Nil(t, err, "expecting nil err")
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+
+	u := attributes(spans[0])["http.url"].AsString()
+	assert.NotContains(t, u, "GB16NWBK40030041426819")
+	assert.NotContains(t, u, "?")
+	assert.Contains(t, u, "/v1/organisation/accounts")
+}
+
+func TestMiddleware_Error(t *testing.T) {
+	c, exporter := setup(t)
+
+	_, _, err := c.Accounts.GetAccount(ctx, uuid.NewString())
+	assert.True(t, errors.Is(err, form3.ErrNotFound), "expecting not found")
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Len(t, spans[0].Events, 1)
+	assert.Equal(t, int64(http.StatusNotFound), attributes(spans[0])["http.status_code"].AsInt64())
+}
+
+func TestMiddleware_RetryCount(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	policy := form3.DefaultRetryPolicy()
+	policy.BaseDelay = 0
+
+	c, exporter := setup(t, form3.WithBaseURL(srv.URL), form3.WithRetryPolicy(policy))
+
+	_, _, err := c.Accounts.GetAccount(ctx, uuid.NewString())
+	assert.Nil(t, err, "expecting nil err")
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, int64(2), attributes(spans[0])[otelform3.RetryCountKey].AsInt64())
+}
+
+func TestMiddleware_NoAttempts(t *testing.T) {
+	shortCircuit := func(next form3.Handler) form3.Handler {
+		return func(ctx context.Context, req *http.Request) (*form3.Response, error) {
+			resp := &form3.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}}
+			return resp, errors.New("short-circuited")
+		}
+	}
+
+	c, exporter := setup(t, form3.WithMiddleware(shortCircuit))
+
+	_, _, err := c.Accounts.GetAccount(ctx, uuid.NewString())
+	assert.NotNil(t, err, "expecting non-nil error")
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.NotContains(t, attributes(spans[0]), otelform3.RetryCountKey)
+	assert.Equal(t, int64(http.StatusServiceUnavailable), attributes(spans[0])["http.status_code"].AsInt64())
+}
+
+func TestMiddleware_Propagation(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c, exporter := setup(t, form3.WithBaseURL(srv.URL))
+
+	_, _, err := c.Accounts.GetAccount(ctx, uuid.NewString())
+	assert.Nil(t, err, "expecting nil err")
+
+	span := exporter.GetSpans()[0]
+	want := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	assert.Equal(t, want, traceparent)
+}
+
+func TestMiddleware_Parent(t *testing.T) {
+	c, exporter := setup(t)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	parentCtx, parent := tp.Tracer("test").Start(ctx, "parent")
+
+	_, _, _ = c.Accounts.GetAccount(parentCtx, uuid.NewString())
+	parent.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, parent.SpanContext().TraceID(), spans[0].SpanContext.TraceID())
+}
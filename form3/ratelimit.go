@@ -0,0 +1,175 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	headerRateLimit     = "X-RateLimit-Limit"
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+)
+
+// Rate represents the rate limit state reported by the API in the
+// X-RateLimit-* response headers. The zero value means no state was reported.
+type Rate struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// parseRate reads the rate limit headers of a response. The reset header may
+// hold either a Unix timestamp or the number of seconds until the reset.
+func parseRate(r *http.Response, now time.Time) Rate {
+	var rate Rate
+
+	if limit := r.Header.Get(headerRateLimit); limit != "" {
+		rate.Limit, _ = strconv.Atoi(limit)
+	}
+
+	if remaining := r.Header.Get(headerRateRemaining); remaining != "" {
+		rate.Remaining, _ = strconv.Atoi(remaining)
+	}
+
+	if reset := r.Header.Get(headerRateReset); reset != "" {
+		if v, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			if v > 1e9 {
+				rate.Reset = time.Unix(v, 0)
+			} else {
+				rate.Reset = now.Add(time.Duration(v) * time.Second)
+			}
+		}
+	}
+
+	return rate
+}
+
+// RateLimiter is a token bucket that throttles outgoing requests. When the API
+// reports its own rate limit state, the bucket never holds more tokens than
+// the requests remaining, and blocks until the reset once none are left. The
+// burst is lowered to the reported limit, and the rate to the limit per
+// window once the length of the window is known from two successive resets.
+type RateLimiter struct {
+	mu        sync.Mutex
+	maxRate   float64
+	maxBurst  float64
+	rate      float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+	until     time.Time
+	reset     time.Time
+	remaining int
+	window    time.Duration
+	clock     clock
+}
+
+// NewRateLimiter returns a RateLimiter allowing requestsPerSecond requests on
+// average, with bursts of up to burst requests. The rate must be positive.
+func NewRateLimiter(requestsPerSecond float64, burst int) (*RateLimiter, error) {
+	if !(requestsPerSecond > 0) || math.IsInf(requestsPerSecond, 1) {
+		return nil, fmt.Errorf("form3: invalid rate limit %v, must be positive", requestsPerSecond)
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		maxRate:  requestsPerSecond,
+		maxBurst: float64(burst),
+		rate:     requestsPerSecond,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		clock:    realClock{},
+	}, nil
+}
+
+// Wait blocks until a request may be sent or ctx is cancelled.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		if err := l.clock.Sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token if one is available, or returns how long to wait for
+// the next one.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.refill(now)
+
+	if now.Before(l.until) {
+		return l.until.Sub(now)
+	}
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+
+	l.last = now
+}
+
+// update adapts the bucket to the rate limit state reported by the API.
+func (l *RateLimiter) update(rate Rate) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(l.clock.Now())
+
+	if rate.Limit > 0 {
+		// A new window started when the remaining requests went up. The
+		// shortest distance seen between two resets is taken as the window,
+		// as windows without requests are not reported.
+		if rate.Remaining > l.remaining && !l.reset.IsZero() && rate.Reset.After(l.reset) {
+			if window := rate.Reset.Sub(l.reset); l.window == 0 || window < l.window {
+				l.window = window
+			}
+		}
+
+		l.burst = math.Min(l.maxBurst, float64(rate.Limit))
+		if l.window > 0 {
+			l.rate = math.Min(l.maxRate, float64(rate.Limit)/l.window.Seconds())
+		}
+	}
+
+	if rate.Reset.After(l.reset) {
+		l.reset = rate.Reset
+	}
+	l.remaining = rate.Remaining
+
+	if limit := math.Min(l.burst, float64(rate.Remaining)); l.tokens > limit {
+		l.tokens = limit
+	}
+
+	if rate.Remaining == 0 && rate.Reset.After(l.until) {
+		l.until = rate.Reset
+	}
+}
@@ -0,0 +1,173 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestRateLimiter(requestsPerSecond float64, burst int) (*RateLimiter, *fakeClock) {
+	clk := &fakeClock{now: time.Date(2022, 10, 23, 15, 50, 0, 0, time.UTC)}
+
+	l, err := NewRateLimiter(requestsPerSecond, burst)
+	if err != nil {
+		panic(err)
+	}
+	l.clock = clk
+
+	return l, clk
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l, clk := newTestRateLimiter(2, 2)
+
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait returned an error: %v", err)
+		}
+	}
+
+	equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{500 * time.Millisecond, 500 * time.Millisecond}))
+}
+
+func TestRateLimiter_Refill(t *testing.T) {
+	l, clk := newTestRateLimiter(1, 1)
+
+	_ = l.Wait(ctx)
+	clk.now = clk.now.Add(time.Minute)
+	_ = l.Wait(ctx)
+
+	equal(t, len(clk.sleeps), 0)
+}
+
+func TestRateLimiter_ContextCancelled(t *testing.T) {
+	l, _ := newTestRateLimiter(1, 1)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_ = l.Wait(ctx)
+
+	if err := l.Wait(cancelCtx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRateLimiter_Update(t *testing.T) {
+	l, clk := newTestRateLimiter(10, 10)
+
+	reset := clk.now.Add(30 * time.Second)
+	l.update(Rate{Limit: 100, Remaining: 0, Reset: reset})
+
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("Wait returned an error: %v", err)
+	}
+
+	equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{30 * time.Second}))
+}
+
+func TestRateLimiter_UpdateRemaining(t *testing.T) {
+	l, clk := newTestRateLimiter(1, 10)
+
+	l.update(Rate{Limit: 100, Remaining: 1, Reset: clk.now.Add(time.Minute)})
+
+	_ = l.Wait(ctx)
+	_ = l.Wait(ctx)
+
+	equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{time.Second}))
+}
+
+func TestRateLimiter_UpdateLimitCapsBurst(t *testing.T) {
+	l, clk := newTestRateLimiter(10, 10)
+
+	l.update(Rate{Limit: 2, Remaining: 2, Reset: clk.now.Add(time.Minute)})
+	clk.now = clk.now.Add(time.Hour)
+
+	for i := 0; i < 3; i++ {
+		_ = l.Wait(ctx)
+	}
+
+	equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{100 * time.Millisecond}))
+}
+
+func TestRateLimiter_UpdateLimitAdaptsRate(t *testing.T) {
+	l, clk := newTestRateLimiter(100, 1)
+
+	start := clk.now
+	l.update(Rate{Limit: 10, Remaining: 5, Reset: start.Add(time.Minute)})
+
+	clk.now = start.Add(time.Minute)
+	l.update(Rate{Limit: 10, Remaining: 9, Reset: start.Add(2 * time.Minute)})
+
+	_ = l.Wait(ctx)
+	_ = l.Wait(ctx)
+
+	equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{6 * time.Second}))
+}
+
+func TestNewRateLimiter_InvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		if _, err := NewRateLimiter(rate, 1); err == nil {
+			t.Errorf("NewRateLimiter(%v) expected an error", rate)
+		}
+	}
+}
+
+func TestSendRequest_RateLimit(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	l, clk := newTestRateLimiter(100, 100)
+	client.RateLimiter = l
+	client.clock = clk
+
+	reset := clk.now.Add(10 * time.Second)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	resp, err := client.SendRequest(req, nil)
+	if err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	want := Rate{Limit: 60, Remaining: 0, Reset: reset}
+	if !cmp.Equal(want, resp.Rate) {
+		t.Errorf("Response Rate; got %+v, want %+v", resp.Rate, want)
+	}
+
+	req, _ = client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	_, err = client.SendRequest(req, nil)
+	if err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{10 * time.Second}))
+}
+
+func TestParseRate_ResetInSeconds(t *testing.T) {
+	now := time.Date(2022, 10, 23, 15, 50, 0, 0, time.UTC)
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Limit", "1000")
+	resp.Header.Set("X-RateLimit-Remaining", "999")
+	resp.Header.Set("X-RateLimit-Reset", "42")
+
+	want := Rate{Limit: 1000, Remaining: 999, Reset: now.Add(42 * time.Second)}
+	if got := parseRate(resp, now); !cmp.Equal(want, got) {
+		t.Errorf("parseRate; got %+v, want %+v", got, want)
+	}
+}
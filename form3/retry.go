@@ -0,0 +1,231 @@
+package form3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// idempotencyKeyHeader marks a POST or PATCH request as safe to retry.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy configures how failed requests are retried. GET, HEAD, OPTIONS,
+// PUT and DELETE requests are retried by default; POST and PATCH requests are
+// only retried when they carry an Idempotency-Key header.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay doubles with
+	// every attempt, up to MaxDelay if it is positive, and is randomised with
+	// full jitter. A Retry-After header sent by the server is also capped at
+	// MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// RetryableStatusCodes are the response status codes that are retried.
+	RetryableStatusCodes []int
+
+	// RetryableError reports whether a transport error is retried. If nil,
+	// timeouts, connection resets and refused connections are retried.
+	RetryableError func(error) bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy that makes up to three attempts
+// and retries rate limiting and transient server errors.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+// retryable reports whether the outcome of an attempt should be retried.
+func (p *RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if !isIdempotent(req) {
+		return false
+	}
+
+	if resp == nil {
+		if err == nil || req.Context().Err() != nil {
+			return false
+		}
+
+		if p.RetryableError != nil {
+			return p.RetryableError(err)
+		}
+
+		return isTransientError(err)
+	}
+
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delay returns the delay before the next attempt. A Retry-After header sent
+// by the server takes precedence over the exponential backoff.
+func (p *RetryPolicy) delay(attempt int, resp *http.Response, now time.Time, jitter func(int64) int64) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After"), now); ok {
+			if p.MaxDelay > 0 && d > p.MaxDelay {
+				d = p.MaxDelay
+			}
+			return d
+		}
+	}
+
+	backoff := p.BaseDelay
+	for i := 1; i < attempt && backoff < math.MaxInt64/2; i++ {
+		if p.MaxDelay > 0 && backoff >= p.MaxDelay {
+			break
+		}
+		backoff *= 2
+	}
+
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	return time.Duration(jitter(int64(backoff) + 1))
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an
+// HTTP date.
+func retryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+
+	return 0, true
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost, http.MethodPatch:
+		return req.Header.Get(idempotencyKeyHeader) != ""
+	}
+
+	return false
+}
+
+func isTransientError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// bufferBody makes sure the request body can be replayed for a retry.
+func bufferBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return nil
+}
+
+// rewindBody resets the request body before it is sent again.
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+
+	return nil
+}
+
+// clock abstracts time so that retry delays can be tested without sleeping.
+type clock interface {
+	Now() time.Time
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
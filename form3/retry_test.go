@@ -0,0 +1,316 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeClock records the delays it is asked to sleep instead of sleeping.
+type fakeClock struct {
+	now     time.Time
+	sleeps  []time.Duration
+	onSleep func()
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	if c.onSleep != nil {
+		c.onSleep()
+	}
+	return ctx.Err()
+}
+
+func setupRetry(policy *RetryPolicy) *fakeClock {
+	clk := &fakeClock{now: time.Date(2022, 10, 23, 15, 50, 0, 0, time.UTC)}
+
+	client.RetryPolicy = policy
+	client.clock = clk
+	client.jitter = func(n int64) int64 { return n - 1 }
+
+	return clk
+}
+
+func TestSendRequest_Retry(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	clk := setupRetry(DefaultRetryPolicy())
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"Field":"v"}`)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	resp, err := client.SendRequest(req, new(json.RawMessage))
+	if err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	equal(t, calls, 3)
+	equal(t, resp.Attempts, 3)
+	equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{100 * time.Millisecond, 200 * time.Millisecond}))
+}
+
+func TestSendRequest_RetryMaxDelay(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	clk := setupRetry(&RetryPolicy{
+		MaxAttempts:          5,
+		BaseDelay:            time.Second,
+		MaxDelay:             3 * time.Second,
+		RetryableStatusCodes: []int{http.StatusInternalServerError},
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodDelete, "/", nil)
+
+	resp, err := client.SendRequest(req, nil)
+	if err, ok := err.(*ErrorResponse); !ok || err.Status != http.StatusInternalServerError {
+		t.Fatalf("Expected the last ErrorResponse, got %v", err)
+	}
+
+	equal(t, resp.Attempts, 5)
+	equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}))
+}
+
+func TestRetryPolicy_DelayWithoutMaxDelay(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 100 * time.Millisecond}
+	noJitter := func(n int64) int64 { return n - 1 }
+
+	var got []time.Duration
+	for attempt := 1; attempt <= 4; attempt++ {
+		got = append(got, policy.delay(attempt, nil, time.Now(), noJitter))
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond}
+	equal(t, fmt.Sprint(got), fmt.Sprint(want))
+
+	if d := policy.delay(100, nil, time.Now(), noJitter); d <= 0 {
+		t.Errorf("delay(100) = %v, want a positive delay", d)
+	}
+}
+
+func TestSendRequest_RetryJitter(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := policy.delay(attempt, nil, time.Now(), rand.Int63n)
+		if d < 0 || d > policy.MaxDelay {
+			t.Errorf("delay(%d) = %v, want between 0 and %v", attempt, d, policy.MaxDelay)
+		}
+	}
+}
+
+func TestSendRequest_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter func(now time.Time) string
+		want       time.Duration
+	}{
+		{
+			name:       "seconds",
+			retryAfter: func(now time.Time) string { return "3" },
+			want:       3 * time.Second,
+		},
+		{
+			name:       "http date",
+			retryAfter: func(now time.Time) string { return now.Add(4 * time.Second).Format(http.TimeFormat) },
+			want:       4 * time.Second,
+		},
+		{
+			name:       "capped at max delay",
+			retryAfter: func(now time.Time) string { return "86400" },
+			want:       5 * time.Second,
+		},
+		{
+			name:       "invalid",
+			retryAfter: func(now time.Time) string { return "soon" },
+			want:       100 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teardown := setup()
+			defer teardown()
+
+			clk := setupRetry(DefaultRetryPolicy())
+
+			calls := 0
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls == 1 {
+					w.Header().Set("Retry-After", tt.retryAfter(clk.now))
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+			_, err := client.SendRequest(req, nil)
+			if err != nil {
+				t.Fatalf("SendRequest returned an error: %v", err)
+			}
+
+			equal(t, fmt.Sprint(clk.sleeps), fmt.Sprint([]time.Duration{tt.want}))
+		})
+	}
+}
+
+func TestSendRequest_NoRetryForPost(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	setupRetry(DefaultRetryPolicy())
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodPost, "/", map[string]string{"id": testUUID})
+
+	resp, err := client.SendRequest(req, nil)
+	if err == nil {
+		t.Fatalf("Expected an HTTP error")
+	}
+
+	equal(t, calls, 1)
+	equal(t, resp.Attempts, 1)
+}
+
+func TestSendRequest_RetryPostWithIdempotencyKey(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	setupRetry(DefaultRetryPolicy())
+
+	var bodies []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			http.Error(w, "Bad gateway", http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodPost, "/", map[string]string{"id": testUUID})
+	req.Header.Set("Idempotency-Key", "a-key")
+
+	// Drop GetBody to check that the body is buffered before the first attempt.
+	req.GetBody = nil
+
+	_, err := client.SendRequest(req, nil)
+	if err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	want := fmt.Sprintf("{\"id\":\"%v\"}\n", testUUID)
+	equal(t, fmt.Sprint(bodies), fmt.Sprint([]string{want, want}))
+}
+
+func TestSendRequest_NoRetryForClientError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	clk := setupRetry(DefaultRetryPolicy())
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "Bad request", http.StatusBadRequest)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	_, err := client.SendRequest(req, nil)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+
+	equal(t, calls, 1)
+	equal(t, len(clk.sleeps), 0)
+}
+
+func TestSendRequest_RetryNetworkError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	setupRetry(DefaultRetryPolicy())
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req, _ := client.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	resp, err := client.SendRequest(req, nil)
+	if err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	equal(t, resp.Attempts, 2)
+}
+
+func TestSendRequest_RetryContextCancelled(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	clk := setupRetry(DefaultRetryPolicy())
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clk.onSleep = cancel
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+	})
+
+	req, _ := client.NewRequest(cancelCtx, http.MethodGet, "/", nil)
+
+	_, err := client.SendRequest(req, nil)
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	equal(t, calls, 1)
+	equal(t, len(clk.sleeps), 1)
+}
@@ -0,0 +1,202 @@
+package form3
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultContentType = "application/vnd.api+json"
+
+// SigningTransport is an http.RoundTripper that signs every request according
+// to the HTTP Signatures draft, as required by the Form3 production
+// environments. It adds a SHA-256 Digest header over the body and an
+// Authorization header signing the (request-target), host, date and digest
+// headers, plus content-type and content-length when the request has a body.
+//
+// Form3 API docs: https://www.api-docs.form3.tech/api/tutorials/getting-started/create-a-signature-key-pair
+type SigningTransport struct {
+	// KeyID identifies the public key registered with Form3.
+	KeyID string
+
+	// Key is the RSA or ECDSA private key used to sign requests.
+	Key crypto.Signer
+
+	// Base is the RoundTripper used to send the signed requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	now func() time.Time
+}
+
+// NewSigningTransport returns a SigningTransport signing requests with key,
+// which must be an *rsa.PrivateKey or *ecdsa.PrivateKey.
+func NewSigningTransport(keyID string, key crypto.Signer, base http.RoundTripper) (*SigningTransport, error) {
+	if keyID == "" {
+		return nil, errors.New("form3: signing key ID is empty")
+	}
+
+	if _, err := signatureAlgorithm(key); err != nil {
+		return nil, err
+	}
+
+	return &SigningTransport{
+		KeyID: keyID,
+		Key:   key,
+		Base:  base,
+	}, nil
+}
+
+// WithSigner signs every request sent by the client with key, wrapping the
+// client's transport in a SigningTransport. The transport is wrapped even if
+// it is set by a later option.
+func WithSigner(keyID string, key crypto.Signer) Option {
+	return func(c *Client) error {
+		t, err := NewSigningTransport(keyID, key, nil)
+		if err != nil {
+			return err
+		}
+
+		c.wrapTransport(func(base http.RoundTripper) http.RoundTripper {
+			t.Base = base
+			return t
+		})
+
+		return nil
+	}
+}
+
+// RoundTrip signs a copy of the request and sends it with the base transport.
+func (t *SigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed, err := t.sign(req)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	return t.base().RoundTrip(signed)
+}
+
+func (t *SigningTransport) sign(req *http.Request) (*http.Request, error) {
+	algorithm, err := signatureAlgorithm(t.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signed := req.Clone(req.Context())
+	if len(body) > 0 {
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+		signed.ContentLength = int64(len(body))
+	} else {
+		signed.Body = nil
+		signed.ContentLength = 0
+	}
+
+	if signed.Host == "" {
+		signed.Host = signed.URL.Host
+	}
+
+	// The date is set when the request is sent rather than built, so that
+	// retries are not signed with a stale date.
+	signed.Header.Set("Date", t.timeNow().UTC().Format(http.TimeFormat))
+
+	digest := sha256.Sum256(body)
+	signed.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+
+	headers := []string{"(request-target)", "host", "date", "digest"}
+
+	if len(body) > 0 {
+		if signed.Header.Get("Content-Type") == "" {
+			signed.Header.Set("Content-Type", defaultContentType)
+		}
+		signed.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+		headers = append(headers, "content-type", "content-length")
+	}
+
+	hashed := sha256.Sum256([]byte(signingString(signed, headers)))
+
+	signature, err := t.Key.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	signed.Header.Set("Authorization", fmt.Sprintf(`Signature keyId="%v",algorithm="%v",headers="%v",signature="%v"`,
+		t.KeyID, algorithm, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+
+	return signed, nil
+}
+
+// signingString builds the string to sign from the given header names.
+func signingString(req *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+
+	for _, h := range headers {
+		var value string
+		switch h {
+		case "(request-target)":
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Host
+		default:
+			value = req.Header.Get(h)
+		}
+
+		lines = append(lines, h+": "+value)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func signatureAlgorithm(key crypto.Signer) (string, error) {
+	if key == nil {
+		return "", errors.New("form3: signing key is nil")
+	}
+
+	switch key.Public().(type) {
+	case *rsa.PublicKey:
+		return "rsa-sha256", nil
+	case *ecdsa.PublicKey:
+		return "ecdsa-sha256", nil
+	}
+
+	return "", fmt.Errorf("form3: unsupported signing key type %T", key)
+}
+
+func (t *SigningTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+
+	return t.Base
+}
+
+func (t *SigningTransport) timeNow() time.Time {
+	if t.now == nil {
+		return time.Now()
+	}
+
+	return t.now()
+}
@@ -0,0 +1,230 @@
+package form3
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var signatureParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// verifySignature checks the Digest and Authorization headers of a signed
+// request against the public key.
+func verifySignature(t *testing.T, r *http.Request, pub crypto.PublicKey) map[string]string {
+	t.Helper()
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Signature ") {
+		t.Fatalf("Authorization header; got %q", auth)
+	}
+
+	params := map[string]string{}
+	for _, m := range signatureParam.FindAllStringSubmatch(auth, -1) {
+		params[m[1]] = m[2]
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+	}
+
+	digest := sha256.Sum256(body)
+	equal(t, r.Header.Get("Digest"), "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		t.Fatalf("Signature is not base64: %v", err)
+	}
+
+	hashed := sha256.Sum256([]byte(signingString(r, strings.Split(params["headers"], " "))))
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, hashed[:], signature) {
+			err = rsa.ErrVerification
+		}
+	}
+
+	if err != nil {
+		t.Errorf("Signature verification failed: %v", err)
+	}
+
+	return params
+}
+
+func TestSigningTransport(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		name      string
+		key       crypto.Signer
+		algorithm string
+		body      interface{}
+		headers   string
+	}{
+		{
+			name:      "rsa without body",
+			key:       rsaKey,
+			algorithm: "rsa-sha256",
+			headers:   "(request-target) host date digest",
+		},
+		{
+			name:      "rsa with body",
+			key:       rsaKey,
+			algorithm: "rsa-sha256",
+			body:      map[string]string{"id": testUUID},
+			headers:   "(request-target) host date digest content-type content-length",
+		},
+		{
+			name:      "ecdsa with body",
+			key:       ecdsaKey,
+			algorithm: "ecdsa-sha256",
+			body:      map[string]string{"id": testUUID},
+			headers:   "(request-target) host date digest content-type content-length",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+				params := verifySignature(t, r, tt.key.Public())
+
+				equal(t, params["keyId"], "a-key-id")
+				equal(t, params["algorithm"], tt.algorithm)
+				equal(t, params["headers"], tt.headers)
+
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			signer, err := NewSigningTransport("a-key-id", tt.key, nil)
+			if err != nil {
+				t.Fatalf("NewSigningTransport returned an error: %v", err)
+			}
+			client.client = &http.Client{Transport: signer}
+
+			req, _ := client.NewRequest(ctx, http.MethodPost, "/v1/organisation/accounts?page%5Bsize%5D=1", tt.body)
+
+			_, err = client.SendRequest(req, nil)
+			if err != nil {
+				t.Fatalf("SendRequest returned an error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSigningTransport_Headers(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	var signed *http.Request
+	base := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		signed = r
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+	})
+
+	signer, _ := NewSigningTransport("a-key-id", key, base)
+	signer.now = func() time.Time { return time.Date(2022, 10, 23, 15, 50, 0, 0, time.UTC) }
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.form3.tech/v1/organisation/accounts", strings.NewReader(`{"data":{}}`))
+
+	_, err := signer.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip returned an error: %v", err)
+	}
+
+	equal(t, signed.Header.Get("Date"), "Sun, 23 Oct 2022 15:50:00 GMT")
+	equal(t, signed.Header.Get("Content-Type"), "application/vnd.api+json")
+	equal(t, signed.Header.Get("Content-Length"), "11")
+	equal(t, signingString(signed, []string{"(request-target)", "host"}), "(request-target): post /v1/organisation/accounts\nhost: api.form3.tech")
+
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("RoundTrip modified the original request")
+	}
+
+	verifySignature(t, signed, key.Public())
+}
+
+func TestWithSigner_Date(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	var dates []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		verifySignature(t, r, key.Public())
+		dates = append(dates, r.Header.Get("Date"))
+		if len(dates) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	c, err := NewClientWithOptions(
+		WithBaseURL(server.URL),
+		WithSigner("a-key-id", key),
+		WithRetryPolicy(DefaultRetryPolicy()),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	clk := &fakeClock{now: time.Date(2022, 10, 23, 17, 49, 59, 950*int(time.Millisecond), time.FixedZone("CEST", 2*60*60))}
+	c.clock = clk
+	c.jitter = func(n int64) int64 { return n - 1 }
+	c.Client().Transport.(*SigningTransport).now = clk.Now
+
+	req, _ := c.NewRequest(ctx, http.MethodGet, "/", nil)
+
+	if _, err := c.SendRequest(req, nil); err != nil {
+		t.Fatalf("SendRequest returned an error: %v", err)
+	}
+
+	equal(t, strings.Join(dates, ", "), "Sun, 23 Oct 2022 15:49:59 GMT, Sun, 23 Oct 2022 15:50:00 GMT")
+	for _, date := range dates {
+		if _, err := http.ParseTime(date); err != nil {
+			t.Errorf("Date %q is not an HTTP date: %v", date, err)
+		}
+	}
+}
+
+func TestNewSigningTransport_Errors(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if _, err := NewSigningTransport("", key, nil); err == nil {
+		t.Errorf("Expected an error for an empty key ID")
+	}
+
+	if _, err := NewSigningTransport("a-key-id", nil, nil); err == nil {
+		t.Errorf("Expected an error for a nil key")
+	}
+}
+
+func TestWithSigner(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	c, err := NewClientWithOptions(WithSigner("a-key-id", key))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions returned an error: %v", err)
+	}
+
+	if _, ok := c.Client().Transport.(*SigningTransport); !ok {
+		t.Errorf("WithSigner Transport; got %T", c.Client().Transport)
+	}
+}
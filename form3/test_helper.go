@@ -38,7 +38,7 @@ func equalRequestBody[T comparable](t *testing.T, r *http.Request, want, got T)
 }
 
 type apiResponse interface {
-	*Account | *AccountResponse
+	*Account | *AccountPatch | *AccountResponse | *AccountListResponse
 }
 
 func createApiResponse[T apiResponse](fileName string) T {
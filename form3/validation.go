@@ -0,0 +1,188 @@
+package form3
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	accountType = "accounts"
+
+	maxNames       = 4
+	maxAltNames    = 3
+	maxNameLength  = 140
+	maxFieldErrors = 100
+)
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	bicPattern  = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+)
+
+// FieldError describes why a single field of a payload is invalid.
+type FieldError struct {
+	// Field is the JSON path of the field, e.g. "data.attributes.country".
+	Field   string
+	Message string
+}
+
+// Error formats the FieldError.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Field, e.Message)
+}
+
+// ValidationError is returned when a payload fails client-side validation.
+// It holds one FieldError per invalid field and matches ErrValidation through
+// errors.Is.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// Error formats the ValidationError.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+
+	return "form3: validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// fieldErrors collects FieldErrors while a payload is validated.
+type fieldErrors []*FieldError
+
+func (errs *fieldErrors) add(field, format string, args ...interface{}) {
+	if len(*errs) < maxFieldErrors {
+		*errs = append(*errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+func (errs fieldErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: errs}
+}
+
+// Validate checks the account before it is sent to the API. It returns a
+// *ValidationError listing every invalid field, or nil.
+func (a *Account) Validate() error {
+	var errs fieldErrors
+
+	if a == nil || a.Data == nil {
+		errs.add("data", "is required")
+		return errs.err()
+	}
+
+	a.Data.validate("data", &errs)
+
+	return errs.err()
+}
+
+// Validate checks the account data before it is sent to the API. It returns
+// a *ValidationError listing every invalid field, or nil.
+func (d *AccountData) Validate() error {
+	var errs fieldErrors
+
+	if d == nil {
+		errs.add("data", "is required")
+		return errs.err()
+	}
+
+	d.validate("data", &errs)
+
+	return errs.err()
+}
+
+func (d *AccountData) validate(path string, errs *fieldErrors) {
+	if d.Type != accountType {
+		errs.add(path+".type", "must be %q, got %q", accountType, d.Type)
+	}
+
+	validateUUID(path+".id", d.ID, errs)
+	validateUUID(path+".organisation_id", d.OrganisationID, errs)
+
+	if d.Attributes == nil {
+		errs.add(path+".attributes", "is required")
+		return
+	}
+
+	d.Attributes.validate(path+".attributes", errs)
+}
+
+func (a *AccountAttributes) validate(path string, errs *fieldErrors) {
+	if a.Country == "" {
+		errs.add(path+".country", "is required")
+	} else if _, ok := countryCodes[a.Country]; !ok {
+		errs.add(path+".country", "%q is not an ISO 3166-1 alpha-2 country code", a.Country)
+	} else {
+		a.validateCountryRule(path, errs)
+	}
+
+	if a.BaseCurrency != "" && !a.BaseCurrency.Valid() {
+		errs.add(path+".base_currency", "%q is not an ISO 4217 currency code", a.BaseCurrency)
+	}
+
+	if a.AccountClassification != "" && !a.AccountClassification.Valid() {
+		errs.add(path+".account_classification", "%q is not a valid account classification", a.AccountClassification)
+	}
+
+	if a.BankIDCode != "" && !a.BankIDCode.Valid() {
+		errs.add(path+".bank_id_code", "%q is not a valid bank ID code", a.BankIDCode)
+	}
+
+	if a.Status != nil && *a.Status != "" && !a.Status.Valid() {
+		errs.add(path+".status", "%q is not a valid account status", *a.Status)
+	}
+
+	if a.Bic != "" && !bicPattern.MatchString(a.Bic) {
+		errs.add(path+".bic", "%q is not a valid BIC", a.Bic)
+	}
+
+	if a.Iban != "" {
+		a.validateIban(path, errs)
+	}
+
+	if len(a.Name) == 0 {
+		errs.add(path+".name", "is required")
+	}
+
+	validateNames(path+".name", a.Name, maxNames, errs)
+	validateNames(path+".alternative_names", a.AlternativeNames, maxAltNames, errs)
+}
+
+func validateUUID(field, v string, errs *fieldErrors) {
+	if v == "" {
+		errs.add(field, "is required")
+	} else if !uuidPattern.MatchString(v) {
+		errs.add(field, "%q is not a valid UUID", v)
+	}
+}
+
+func validateNames(field string, names []string, max int, errs *fieldErrors) {
+	if len(names) > max {
+		errs.add(field, "must have at most %d items, got %d", max, len(names))
+	}
+
+	for i, name := range names {
+		item := fmt.Sprintf("%v[%d]", field, i)
+
+		switch {
+		case strings.TrimSpace(name) == "":
+			errs.add(item, "must not be blank")
+		case utf8.RuneCountInString(name) > maxNameLength:
+			errs.add(item, "must be at most %d characters", maxNameLength)
+		case strings.IndexFunc(name, unicode.IsControl) >= 0:
+			errs.add(item, "must not contain control characters")
+		}
+	}
+}
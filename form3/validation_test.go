@@ -0,0 +1,182 @@
+package form3
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAccount_Validate(t *testing.T) {
+	account := createApiResponse[*Account](testdataPath + "create-account.json")
+
+	if err := account.Validate(); err != nil {
+		t.Errorf("Validate returned an error: %v", err)
+	}
+}
+
+func TestAccount_ValidateFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(a *Account)
+		want   []*FieldError
+	}{
+		{
+			name:   "missing data",
+			modify: func(a *Account) { a.Data = nil },
+			want:   []*FieldError{{"data", "is required"}},
+		},
+		{
+			name:   "wrong type",
+			modify: func(a *Account) { a.Data.Type = "payments" },
+			want:   []*FieldError{{"data.type", `must be "accounts", got "payments"`}},
+		},
+		{
+			name: "invalid ids",
+			modify: func(a *Account) {
+				a.Data.ID = ""
+				a.Data.OrganisationID = "not-a-uuid"
+			},
+			want: []*FieldError{
+				{"data.id", "is required"},
+				{"data.organisation_id", `"not-a-uuid" is not a valid UUID`},
+			},
+		},
+		{
+			name:   "missing attributes",
+			modify: func(a *Account) { a.Data.Attributes = nil },
+			want:   []*FieldError{{"data.attributes", "is required"}},
+		},
+		{
+			name:   "invalid country",
+			modify: func(a *Account) { a.Data.Attributes.Country = "UK" },
+			want:   []*FieldError{{"data.attributes.country", `"UK" is not an ISO 3166-1 alpha-2 country code`}},
+		},
+		{
+			name:   "invalid currency",
+			modify: func(a *Account) { a.Data.Attributes.BaseCurrency = "GBX" },
+			want:   []*FieldError{{"data.attributes.base_currency", `"GBX" is not an ISO 4217 currency code`}},
+		},
+		{
+			name:   "invalid account classification",
+			modify: func(a *Account) { a.Data.Attributes.AccountClassification = "Charity" },
+			want:   []*FieldError{{"data.attributes.account_classification", `"Charity" is not a valid account classification`}},
+		},
+		{
+			name:   "invalid status",
+			modify: func(a *Account) { a.Data.Attributes.Status = Ptr(AccountStatus("open")) },
+			want:   []*FieldError{{"data.attributes.status", `"open" is not a valid account status`}},
+		},
+		{
+			name:   "invalid bank id code",
+			modify: func(a *Account) { a.Data.Attributes.BankIDCode = "GBDS" },
+			want: []*FieldError{
+				{"data.attributes.bank_id_code", `must be "GBDSC", got "GBDS"`},
+				{"data.attributes.bank_id_code", `"GBDS" is not a valid bank ID code`},
+			},
+		},
+		{
+			name:   "invalid bic",
+			modify: func(a *Account) { a.Data.Attributes.Bic = "NWBK22" },
+			want:   []*FieldError{{"data.attributes.bic", `"NWBK22" is not a valid BIC`}},
+		},
+		{
+			name:   "missing name",
+			modify: func(a *Account) { a.Data.Attributes.Name = nil },
+			want:   []*FieldError{{"data.attributes.name", "is required"}},
+		},
+		{
+			name: "invalid names",
+			modify: func(a *Account) {
+				a.Data.Attributes.Name = []string{"Samantha", " ", strings.Repeat("a", 141), "Sam\nHolder", "Holder"}
+			},
+			want: []*FieldError{
+				{"data.attributes.name", "must have at most 4 items, got 5"},
+				{"data.attributes.name[1]", "must not be blank"},
+				{"data.attributes.name[2]", "must be at most 140 characters"},
+				{"data.attributes.name[3]", "must not contain control characters"},
+			},
+		},
+		{
+			name:   "too many alternative names",
+			modify: func(a *Account) { a.Data.Attributes.AlternativeNames = []string{"a", "b", "c", "d"} },
+			want:   []*FieldError{{"data.attributes.alternative_names", "must have at most 3 items, got 4"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := createApiResponse[*Account](testdataPath + "create-account.json")
+			tt.modify(account)
+
+			err := account.Validate()
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+
+			if !cmp.Equal(tt.want, validationErr.Errors) {
+				t.Error(cmp.Diff(tt.want, validationErr.Errors))
+			}
+
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected ValidationError to match ErrValidation")
+			}
+		})
+	}
+}
+
+func TestValidate_Nil(t *testing.T) {
+	want := &ValidationError{Errors: []*FieldError{{"data", "is required"}}}
+
+	for _, err := range []error{(*Account)(nil).Validate(), (*AccountData)(nil).Validate()} {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected a ValidationError, got %v", err)
+		}
+
+		if !cmp.Equal(want, validationErr) {
+			t.Error(cmp.Diff(want, validationErr))
+		}
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{Errors: []*FieldError{
+		{"data.id", "is required"},
+		{"data.attributes.country", "is required"},
+	}}
+
+	equal(t, err.Error(), "form3: validation failed: data.id: is required; data.attributes.country: is required")
+}
+
+func TestCreateAccount_Validation(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	client.ValidateRequests = true
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("CreateAccount sent an invalid account")
+	})
+
+	body := createApiResponse[*Account](testdataPath + "create-account.json")
+	body.Data.ID = ""
+
+	account, resp, err := client.Accounts.CreateAccount(ctx, body)
+
+	if account != nil || resp != nil {
+		t.Errorf("Expected no account and response")
+	}
+
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+
+	if _, _, err := client.Accounts.CreateAccount(ctx, nil); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error for a nil account, got %v", err)
+	}
+}